@@ -0,0 +1,247 @@
+// Command geojson2svg renders geojson geometries, features and
+// featurecollections as SVG images.
+//
+// Usage:
+//
+//	geojson2svg [flags] [file ...]
+//
+// The geojson objects are read from the given files, or from stdin if no
+// file (or "-") is given. The resulting SVG is written to stdout unless an
+// output file is specified with -o. With -format the drawing is written as
+// PNG, PDF or as an interactive HTML page instead.
+//
+// With the serve subcommand geojson2svg runs a HTTP server rendering the
+// geojson POSTed to it, see geojson2svg.Handler for the query parameters:
+//
+//	geojson2svg serve [-addr :8080] [-max-bytes n] [-max-size n] [-timeout 30s] [-tiles file]
+//
+// With -tiles the geojson of the file is additionally served as XYZ tiles
+// under /tiles/{z}/{x}/{y}.svg and /tiles/{z}/{x}/{y}.png.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fapian/geojson2svg/pkg/geojson2svg"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		if err == flag.ErrHelp {
+			os.Exit(2)
+		}
+		fmt.Fprintf(os.Stderr, "geojson2svg: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) > 0 && args[0] == "serve" {
+		srv, err := newServer(args[1:])
+		if err != nil {
+			return err
+		}
+		return srv.ListenAndServe()
+	}
+
+	fs := flag.NewFlagSet("geojson2svg", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: geojson2svg [flags] [file ...]\n\n")
+		fs.PrintDefaults()
+	}
+
+	width := fs.Float64("width", 400, "width of the svg")
+	height := fs.Float64("height", 400, "height of the svg")
+	output := fs.String("o", "", "output file (default stdout)")
+	format := fs.String("format", "", "output format svg, png, pdf or html (default from the extension of -o, else svg)")
+	props := fs.String("props", "class", "comma separated list of properties copied to the svg elements")
+	ids := fs.Bool("ids", false, "emit the feature ids as id attributes")
+	data := fs.String("data", "", "comma separated list of properties emitted as data-* attributes, '*' for all")
+	css := fs.String("css", "", "stylesheet file embedded into the svg")
+	classes := fs.String("classes", "", "comma separated list of properties used for automatic classes")
+	var padding paddingFlag
+	fs.Var(&padding, "padding", "padding as 'all', 'vertical,horizontal' or 'top,right,bottom,left'")
+	attributes := attributesFlag{}
+	fs.Var(attributes, "attr", "attribute of the svg root element as key=value (repeatable)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if padding.Left+padding.Right >= *width || padding.Top+padding.Bottom >= *height {
+		return errors.New("padding leaves no space for drawing")
+	}
+
+	svg := geojson2svg.New()
+	draw, err := drawFunc(svg, *format, *output)
+	if err != nil {
+		return err
+	}
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	for _, f := range files {
+		if err := addFile(svg, f, stdin); err != nil {
+			return err
+		}
+	}
+
+	opts := []geojson2svg.Option{
+		geojson2svg.WithPadding(geojson2svg.Padding(padding)),
+		geojson2svg.WithAttributes(attributes),
+		geojson2svg.UseProperties(geojson2svg.ParseProperties(*props)),
+	}
+	if *css != "" {
+		bs, err := ioutil.ReadFile(*css)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, geojson2svg.WithStylesheet(string(bs)))
+	}
+	if *classes != "" {
+		opts = append(opts, geojson2svg.WithAutoClasses(geojson2svg.ParseProperties(*classes)))
+	}
+	if *ids {
+		opts = append(opts, geojson2svg.WithFeatureIDs())
+	}
+	if *data == "*" {
+		opts = append(opts, geojson2svg.WithDataAttributes(nil))
+	} else if *data != "" {
+		opts = append(opts, geojson2svg.WithDataAttributes(geojson2svg.ParseProperties(*data)))
+	}
+	if *output == "" {
+		return draw(stdout, *width, *height, opts...)
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := draw(f, *width, *height, opts...); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// newServer returns the rendering server configured by the flags of the
+// serve subcommand.
+func newServer(args []string) (*http.Server, error) {
+	fs := flag.NewFlagSet("geojson2svg serve", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: geojson2svg serve [flags]\n\n")
+		fs.PrintDefaults()
+	}
+
+	addr := fs.String("addr", ":8080", "address to listen on")
+	maxBytes := fs.Int64("max-bytes", geojson2svg.DefaultMaxBytes, "maximum size of request bodies in bytes")
+	maxSize := fs.Float64("max-size", geojson2svg.DefaultMaxSize, "maximum width and height of drawings in pixels")
+	timeout := fs.Duration("timeout", 30*time.Second, "timeout for reading, rendering and writing a request")
+	tiles := fs.String("tiles", "", "geojson file served as tiles under /tiles/{z}/{x}/{y}.svg")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+
+	var h http.Handler = &geojson2svg.Handler{MaxBytes: *maxBytes, MaxSize: *maxSize}
+	if *tiles != "" {
+		svg := geojson2svg.New()
+		if err := addFile(svg, *tiles, os.Stdin); err != nil {
+			return nil, err
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/", h)
+		mux.Handle("/tiles/", &geojson2svg.TileHandler{SVG: svg})
+		h = mux
+	}
+	return &http.Server{
+		Addr:         *addr,
+		Handler:      http.TimeoutHandler(h, *timeout, "timeout"),
+		ReadTimeout:  *timeout,
+		WriteTimeout: 2 * *timeout,
+	}, nil
+}
+
+// drawFunc returns the draw method of svg for the format. Without format
+// it is derived from the extension of the output file.
+func drawFunc(svg *geojson2svg.SVG, format, output string) (func(io.Writer, float64, float64, ...geojson2svg.Option) error, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(output)), ".")
+		if format != "png" && format != "pdf" && format != "html" {
+			format = "svg"
+		}
+	}
+	switch format {
+	case "svg":
+		return svg.DrawTo, nil
+	case "png":
+		return svg.DrawPNG, nil
+	case "pdf":
+		return svg.DrawPDF, nil
+	case "html":
+		return func(w io.Writer, width, height float64, opts ...geojson2svg.Option) error {
+			return svg.DrawHTML(w, width, height, geojson2svg.HTML{PanZoom: true, Highlight: true}, opts...)
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown format: %q", format)
+}
+
+func addFile(svg *geojson2svg.SVG, name string, stdin io.Reader) error {
+	if name == "-" {
+		return svg.AddFromReader(stdin)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := svg.AddFromReader(f); err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	return nil
+}
+
+type paddingFlag geojson2svg.Padding
+
+func (p *paddingFlag) String() string {
+	return fmt.Sprintf("%g,%g,%g,%g", p.Top, p.Right, p.Bottom, p.Left)
+}
+
+func (p *paddingFlag) Set(s string) error {
+	v, err := geojson2svg.ParsePadding(s)
+	if err != nil {
+		return err
+	}
+	*p = paddingFlag(v)
+	return nil
+}
+
+type attributesFlag map[string]string
+
+func (as attributesFlag) String() string {
+	res := []string{}
+	for k, v := range as {
+		res = append(res, k+"="+v)
+	}
+	return strings.Join(res, ",")
+}
+
+func (as attributesFlag) Set(s string) error {
+	kv := strings.SplitN(s, "=", 2)
+	if len(kv) != 2 || kv[0] == "" {
+		return errors.New("attribute must be given as key=value")
+	}
+	as[kv[0]] = kv[1]
+	return nil
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+const featureCollection = `{"type": "FeatureCollection", "features": [
+	{"type": "Feature", "properties": {"class": "c", "style": "s"}, "geometry": {
+		"type": "LineString",
+		"coordinates": [[0,0], [0,400], [400,400], [400,0]]
+	}}
+]}`
+
+func TestRun(t *testing.T) {
+	tcs := []struct {
+		name     string
+		args     []string
+		input    string
+		expected string
+	}{
+		{"defaults",
+			nil,
+			featureCollection,
+			`<svg width="400.000000" height="400.000000"><path d="M0.000000 400.000000,0.000000 0.000000,400.000000 0.000000,400.000000 400.000000" class="c"/></svg>`},
+		{"with size and padding",
+			[]string{"-width", "200", "-height", "200", "-padding", "5"},
+			featureCollection,
+			`<svg width="200.000000" height="200.000000"><path d="M5.000000 195.000000,5.000000 5.000000,195.000000 5.000000,195.000000 195.000000" class="c"/></svg>`},
+		{"with attributes and properties",
+			[]string{"-attr", "id=map", "-attr", "xmlns=http://www.w3.org/2000/svg", "-props", "style", "-"},
+			featureCollection,
+			`<svg width="400.000000" height="400.000000" id="map" xmlns="http://www.w3.org/2000/svg"><path d="M0.000000 400.000000,0.000000 0.000000,400.000000 0.000000,400.000000 400.000000" style="s"/></svg>`},
+		{"without properties",
+			[]string{"-props", ""},
+			featureCollection,
+			`<svg width="400.000000" height="400.000000"><path d="M0.000000 400.000000,0.000000 0.000000,400.000000 0.000000,400.000000 400.000000"/></svg>`},
+		{"with data attributes",
+			[]string{"-props", "", "-data", "*"},
+			featureCollection,
+			`<svg width="400.000000" height="400.000000"><path d="M0.000000 400.000000,0.000000 0.000000,400.000000 0.000000,400.000000 400.000000" data-class="c" data-style="s"/></svg>`},
+		{"with automatic classes",
+			[]string{"-classes", "style"},
+			featureCollection,
+			`<svg width="400.000000" height="400.000000"><path d="M0.000000 400.000000,0.000000 0.000000,400.000000 0.000000,400.000000 400.000000" class="line style-s c"/></svg>`},
+		{"with a geometry",
+			nil,
+			`{"type": "LineString", "coordinates": [[0,0], [400,400]]}`,
+			`<svg width="400.000000" height="400.000000"><path d="M0.000000 400.000000,400.000000 0.000000"/></svg>`},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			stdout := bytes.NewBufferString("")
+			if err := run(tc.args, strings.NewReader(tc.input), stdout); err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			if got := stdout.String(); got != tc.expected {
+				tt.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestRunWithFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "geojson2svg")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	out := path.Join(dir, "out.svg")
+	example := path.Join("..", "..", "test", "example.json")
+	args := []string{"-width", "1000", "-height", "510", "-padding", "10",
+		"-attr", "xmlns=http://www.w3.org/2000/svg", "-props", "style", "-o", out, example}
+	if err := run(args, nil, nil); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	got, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	want, err := ioutil.ReadFile(path.Join("..", "..", "test", "example.svg"))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestRunFormats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "geojson2svg")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tcs := []struct {
+		name   string
+		args   []string
+		prefix string
+	}{
+		{"svg", []string{"-format", "svg"}, "<svg"},
+		{"png", []string{"-format", "png"}, "\x89PNG"},
+		{"pdf", []string{"-format", "pdf"}, "%PDF-1.4"},
+		{"html", []string{"-format", "html"}, "<!DOCTYPE html>"},
+		{"png from extension", []string{"-o", path.Join(dir, "map.png")}, "\x89PNG"},
+		{"pdf from extension", []string{"-o", path.Join(dir, "map.PDF")}, "%PDF-1.4"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			stdout := bytes.NewBufferString("")
+			if err := run(tc.args, strings.NewReader(featureCollection), stdout); err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			got := stdout.Bytes()
+			if len(tc.args) == 2 && tc.args[0] == "-o" {
+				if got, err = ioutil.ReadFile(tc.args[1]); err != nil {
+					tt.Fatalf("unexpected error %v", err)
+				}
+			}
+			if !bytes.HasPrefix(got, []byte(tc.prefix)) {
+				tt.Errorf("expected prefix %q, got %.20q", tc.prefix, got)
+			}
+		})
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tcs := []struct {
+		name string
+		args []string
+		in   string
+	}{
+		{"invalid padding", []string{"-padding", "1,2,3"}, featureCollection},
+		{"negative padding", []string{"-padding", "-1"}, featureCollection},
+		{"padding larger than the drawing", []string{"-width", "100", "-padding", "0,50"}, featureCollection},
+		{"invalid attribute", []string{"-attr", "id"}, featureCollection},
+		{"invalid input", nil, `{"type": "FeatureCollection"`},
+		{"unknown geojson type", nil, `{"type": "Circle"}`},
+		{"missing file", []string{"does-not-exist.json"}, ""},
+		{"unknown format", []string{"-format", "gif"}, featureCollection},
+		{"serve with arguments", []string{"serve", "file.json"}, ""},
+		{"serve with invalid timeout", []string{"serve", "-timeout", "x"}, ""},
+		{"serve missing tiles", []string{"serve", "-tiles", "does-not-exist.json"}, ""},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			stdout := bytes.NewBufferString("")
+			if err := run(tc.args, strings.NewReader(tc.in), stdout); err == nil {
+				tt.Errorf("expected an error, got %s", stdout)
+			}
+		})
+	}
+}
+
+func TestNewServer(t *testing.T) {
+	srv, err := newServer([]string{"-addr", ":9000", "-max-bytes", "10", "-max-size", "100", "-timeout", "5s"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if srv.Addr != ":9000" || srv.ReadTimeout.String() != "5s" {
+		t.Errorf("unexpected server %+v", srv)
+	}
+
+	rec := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rec, httptest.NewRequest("POST", "/", strings.NewReader(featureCollection)))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status 413, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rec, httptest.NewRequest("POST", "/?width=200", strings.NewReader(featureCollection)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", rec.Code)
+	}
+}
+
+func TestNewServerWithTiles(t *testing.T) {
+	srv, err := newServer([]string{"-tiles", path.Join("..", "..", "test", "example.json")})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rec, httptest.NewRequest("GET", "/tiles/0/0/0.svg", nil))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Body.String(), "<svg") {
+		t.Errorf("expected a tile, got %d %s", rec.Code, rec.Body.String())
+	}
+	rec = httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rec, httptest.NewRequest("POST", "/", strings.NewReader(featureCollection)))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", rec.Code)
+	}
+}
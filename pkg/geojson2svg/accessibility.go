@@ -0,0 +1,140 @@
+package geojson2svg
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"text/template"
+
+	geojson "github.com/paulmach/go.geojson"
+)
+
+// The ids of the title and the description of the svg.
+const (
+	titleID       = "svg-title"
+	descriptionID = "svg-desc"
+)
+
+// WithTitle configures the SVG to emit a <title> describing the whole map.
+// The svg gets the role img and is labelled by the title, so screen readers
+// announce it as an image.
+func WithTitle(title string) Option {
+	return func(svg *SVG) {
+		svg.title = title
+	}
+}
+
+// WithDescription configures the SVG to emit a <desc> with a longer
+// description of the whole map, which also labels the svg.
+func WithDescription(desc string) Option {
+	return func(svg *SVG) {
+		svg.description = desc
+	}
+}
+
+// WithFeatureTitle configures the SVG to emit a <title> for every feature,
+// which browsers show as tooltip. The title is the result of the
+// text/template tmpl executed with the properties of the feature, e.g.
+// `{{.name}} ({{printf "%.0f" .population}})`. Features missing a property
+// used by the template get no title. An invalid template is reported by
+// DrawTo.
+func WithFeatureTitle(tmpl string) Option {
+	return func(svg *SVG) {
+		svg.featureTitle = parseTemplate("title", tmpl)
+	}
+}
+
+// WithFeatureDescription configures the SVG to emit a <desc> for every
+// feature, see WithFeatureTitle for the template.
+func WithFeatureDescription(tmpl string) Option {
+	return func(svg *SVG) {
+		svg.featureDescription = parseTemplate("description", tmpl)
+	}
+}
+
+// featureTemplate is a parsed feature template or the error of parsing it.
+// t fails on missing keys, lenient prints them as "<no value>" and tells
+// missing keys apart from other errors.
+type featureTemplate struct {
+	t, lenient *template.Template
+	err        error
+}
+
+func parseTemplate(name, tmpl string) *featureTemplate {
+	lenient, err := template.New(name).Parse(tmpl)
+	if err != nil {
+		return &featureTemplate{err: fmt.Errorf("invalid feature %s template: %v", name, err)}
+	}
+	t := template.Must(template.New(name).Option("missingkey=error").Parse(tmpl))
+	return &featureTemplate{t: t, lenient: lenient}
+}
+
+// templateErr returns the error of parsing the feature templates.
+func (svg *SVG) templateErr() error {
+	for _, ft := range []*featureTemplate{svg.featureTitle, svg.featureDescription} {
+		if ft != nil && ft.err != nil {
+			return ft.err
+		}
+	}
+	return nil
+}
+
+// accessibilityAttributes returns the role and labels of the svg root,
+// attributes set explicitly take precedence.
+func (svg *SVG) accessibilityAttributes() string {
+	ids := []string{}
+	if svg.title != "" {
+		ids = append(ids, svg.prefix()+titleID)
+	}
+	if svg.description != "" {
+		ids = append(ids, svg.prefix()+descriptionID)
+	}
+	if len(ids) == 0 {
+		return ""
+	}
+	res := bytes.NewBufferString("")
+	if _, ok := svg.attributes["role"]; !ok {
+		res.WriteString(` role="img"`)
+	}
+	if _, ok := svg.attributes["aria-labelledby"]; !ok {
+		fmt.Fprintf(res, ` aria-labelledby="%s"`, escapeText(strings.Join(ids, " ")))
+	}
+	return res.String()
+}
+
+// drawMetadata draws the title and the description of the svg.
+func (svg *SVG) drawMetadata(w io.Writer) {
+	if svg.title != "" {
+		fmt.Fprintf(w, `<title id="%s">%s</title>`, escapeText(svg.prefix()+titleID), escapeText(svg.title))
+	}
+	if svg.description != "" {
+		fmt.Fprintf(w, `<desc id="%s">%s</desc>`, escapeText(svg.prefix()+descriptionID), escapeText(svg.description))
+	}
+}
+
+// featureMetadata returns the title and the description of the feature f.
+func (svg *SVG) featureMetadata(f *geojson.Feature) (title, desc string, err error) {
+	if title, err = executeTemplate(svg.featureTitle, f.Properties); err != nil {
+		return "", "", err
+	}
+	if desc, err = executeTemplate(svg.featureDescription, f.Properties); err != nil {
+		return "", "", err
+	}
+	return title, desc, nil
+}
+
+func executeTemplate(ft *featureTemplate, props map[string]interface{}) (string, error) {
+	if ft == nil || ft.t == nil {
+		return "", nil
+	}
+	res := bytes.NewBufferString("")
+	if err := ft.t.Execute(res, props); err != nil {
+		if ft.lenient.Execute(ioutil.Discard, props) == nil {
+			return "", nil
+		}
+		return "", err
+	}
+	return res.String(), nil
+}
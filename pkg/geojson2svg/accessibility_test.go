@@ -0,0 +1,115 @@
+package geojson2svg_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fapian/geojson2svg/pkg/geojson2svg"
+)
+
+func TestAccessibility(t *testing.T) {
+	tcs := []struct {
+		name     string
+		opts     []geojson2svg.Option
+		expected string
+	}{
+		{"title",
+			[]geojson2svg.Option{geojson2svg.WithTitle("Population <2020>")},
+			`<svg width="100" height="100" role="img" aria-labelledby="svg-title"><title id="svg-title">Population &lt;2020&gt;</title>`},
+		{"title and description",
+			[]geojson2svg.Option{geojson2svg.WithTitle("Population"), geojson2svg.WithDescription("by state")},
+			`<svg width="100" height="100" role="img" aria-labelledby="svg-title svg-desc"><title id="svg-title">Population</title><desc id="svg-desc">by state</desc>`},
+		{"explicit role",
+			[]geojson2svg.Option{geojson2svg.WithTitle("Population"), geojson2svg.WithAttribute("role", "graphics-document")},
+			`<svg width="100" height="100" aria-labelledby="svg-title" role="graphics-document"><title id="svg-title">Population</title>`},
+		{"feature title and description",
+			[]geojson2svg.Option{
+				geojson2svg.WithFeatureTitle("{{.name}}"),
+				geojson2svg.WithFeatureDescription("{{.population}} inhabitants"),
+				geojson2svg.WithFeatureIDs(),
+			},
+			`<svg width="100" height="100"><g id="b"><title>Berlin &amp; Brandenburg</title><desc>3500 inhabitants</desc><circle cx="50" cy="50" r="1"/></g>`},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			svg := geojson2svg.New()
+			err := svg.AddFeature(`{"type": "Feature", "id": "b", "geometry": {"type": "Point", "coordinates": [0,0]},
+				"properties": {"name": "Berlin & Brandenburg", "population": 3500}}`)
+			if err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			got := svg.Draw(100, 100, append(tc.opts, geojson2svg.WithPrecision(0))...)
+			if !strings.HasPrefix(got, tc.expected) {
+				tt.Errorf("expected prefix %s\ngot %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestFeatureTemplateMissingProperty(t *testing.T) {
+	svg := geojson2svg.New()
+	err := svg.AddFeature(`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0,0]}, "properties": {"name": "x"}}`)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	expected := `<svg width="100" height="100"><g><desc>x</desc><circle cx="50" cy="50" r="1"/></g></svg>`
+	got := svg.Draw(100, 100,
+		geojson2svg.WithFeatureTitle("{{.name}} ({{.population}})"),
+		geojson2svg.WithFeatureDescription("{{.name}}"),
+		geojson2svg.WithPrecision(0))
+	if got != expected {
+		t.Errorf("expected %s\ngot %s", expected, got)
+	}
+}
+
+func TestFeatureTemplateErrors(t *testing.T) {
+	tcs := []struct {
+		name string
+		opt  geojson2svg.Option
+	}{
+		{"invalid template", geojson2svg.WithFeatureTitle("{{.name")},
+		{"failing template", geojson2svg.WithFeatureDescription("{{.name.first}}")},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			svg := geojson2svg.New()
+			err := svg.AddFeature(`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0,0]}, "properties": {"name": "x"}}`)
+			if err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			if err := svg.DrawTo(&bytes.Buffer{}, 100, 100, tc.opt); err == nil {
+				tt.Errorf("expected an error")
+			}
+		})
+	}
+}
+
+func TestFeatureTemplateReplaced(t *testing.T) {
+	svg := geojson2svg.New()
+	err := svg.AddFeature(`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0,0]}, "properties": {"name": "x"}}`)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	l := svg.Layer("l", geojson2svg.WithFeatureDescription("{{"))
+	if err := l.AddFeature(`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [1,1]}, "properties": {"name": "y"}}`); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if err := svg.DrawTo(&bytes.Buffer{}, 100, 100, geojson2svg.WithFeatureTitle("{{")); err == nil {
+		t.Errorf("expected an error")
+	}
+	if err := svg.DrawTo(&bytes.Buffer{}, 100, 100, geojson2svg.WithFeatureTitle("{{.name}}")); err == nil {
+		t.Errorf("expected an error of the layer")
+	}
+
+	svg.Layer("l", geojson2svg.WithFeatureDescription("{{.name}}"))
+	got := svg.Draw(100, 100)
+	for _, want := range []string{"<title>x</title>", "<desc>y</desc>"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %s to contain %s", got, want)
+		}
+	}
+}
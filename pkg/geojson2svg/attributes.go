@@ -0,0 +1,139 @@
+package geojson2svg
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// A KeyPolicy decides what happens with attribute names which are not
+// valid XML names, e.g. property keys containing spaces or quotes.
+type KeyPolicy int
+
+// The policies for invalid attribute names.
+const (
+	// DropKeys omits the attributes with invalid names.
+	DropKeys KeyPolicy = iota
+	// SanitizeKeys replaces the invalid characters of the names by
+	// underscores.
+	SanitizeKeys
+	// RejectKeys makes DrawTo fail with an error, Draw drops the names.
+	RejectKeys
+)
+
+// WithKeyPolicy configures how the SVG handles attribute names which are
+// not valid XML names. Attribute values are always escaped.
+func WithKeyPolicy(p KeyPolicy) Option {
+	return func(svg *SVG) {
+		svg.keyPolicy = p
+	}
+}
+
+// attributeEncoder encodes attributes according to a key policy and
+// remembers the first invalid name if they are rejected.
+type attributeEncoder struct {
+	policy KeyPolicy
+	err    error
+}
+
+// encode returns the attributes sorted by key. A sanitized name which is
+// already used, by a valid key or an earlier sanitized one, is dropped.
+func (e *attributeEncoder) encode(as map[string]string) string {
+	keys := make([]string, 0, len(as))
+	for k := range as {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	used := map[string]bool{}
+	for _, k := range keys {
+		if isName(k) {
+			used[k] = true
+		}
+	}
+	res := bytes.NewBufferString("")
+	for _, k := range keys {
+		name := k
+		if !isName(k) {
+			switch e.policy {
+			case SanitizeKeys:
+				name = sanitizeName(k)
+				if used[name] {
+					continue
+				}
+				used[name] = true
+			case RejectKeys:
+				if e.err == nil {
+					e.err = fmt.Errorf("invalid attribute name: %q", k)
+				}
+				continue
+			default:
+				continue
+			}
+		}
+		fmt.Fprintf(res, ` %s="%s"`, name, escapeText(as[k]))
+	}
+	return res.String()
+}
+
+// isName reports whether s is a valid XML name.
+func isName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if i == 0 && !isNameStart(r) || !isNameChar(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// sanitizeName returns s with all characters which are not allowed in a
+// XML name replaced by underscores.
+func sanitizeName(s string) string {
+	res := bytes.NewBufferString("")
+	for i, r := range s {
+		switch {
+		case i == 0 && !isNameStart(r) && isNameChar(r):
+			res.WriteRune('_')
+			res.WriteRune(r)
+		case r == utf8.RuneError || !isNameChar(r):
+			res.WriteRune('_')
+		default:
+			res.WriteRune(r)
+		}
+	}
+	if res.Len() == 0 {
+		return "_"
+	}
+	return res.String()
+}
+
+// isNameStart reports whether r is a NameStartChar of the XML
+// specification.
+func isNameStart(r rune) bool {
+	switch {
+	case r == ':' || r == '_' || 'A' <= r && r <= 'Z' || 'a' <= r && r <= 'z':
+		return true
+	case 0xC0 <= r && r <= 0xD6, 0xD8 <= r && r <= 0xF6, 0xF8 <= r && r <= 0x2FF,
+		0x370 <= r && r <= 0x37D, 0x37F <= r && r <= 0x1FFF, 0x200C <= r && r <= 0x200D,
+		0x2070 <= r && r <= 0x218F, 0x2C00 <= r && r <= 0x2FEF, 0x3001 <= r && r <= 0xD7FF,
+		0xF900 <= r && r <= 0xFDCF, 0xFDF0 <= r && r <= 0xFFFD, 0x10000 <= r && r <= 0xEFFFF:
+		return true
+	}
+	return false
+}
+
+// isNameChar reports whether r is a NameChar of the XML specification.
+func isNameChar(r rune) bool {
+	switch {
+	case isNameStart(r):
+		return true
+	case r == '-' || r == '.' || '0' <= r && r <= '9' || r == 0xB7:
+		return true
+	case 0x300 <= r && r <= 0x36F, 0x203F <= r && r <= 0x2040:
+		return true
+	}
+	return false
+}
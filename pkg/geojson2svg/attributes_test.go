@@ -0,0 +1,119 @@
+package geojson2svg_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fapian/geojson2svg/pkg/geojson2svg"
+)
+
+func TestAttributeEscaping(t *testing.T) {
+	tcs := []struct {
+		name     string
+		policy   geojson2svg.KeyPolicy
+		expected string
+	}{
+		{"drop invalid keys",
+			geojson2svg.DropKeys,
+			`<circle cx="50.000000" cy="50.000000" r="1" data-ok="1" name="A &#34;quoted&#34; &lt;b&gt; &amp; &#39;x&#39;"/>`},
+		{"sanitize invalid keys",
+			geojson2svg.SanitizeKeys,
+			`<circle cx="50.000000" cy="50.000000" r="1" _1st="a" bad_key_="b" data-ok="1" name="A &#34;quoted&#34; &lt;b&gt; &amp; &#39;x&#39;"/>`},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			svg := geojson2svg.New()
+			err := svg.AddFeature(`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0,0]},
+				"properties": {"name": "A \"quoted\" <b> & 'x'", "bad key\"": "b", "1st": "a", "data-ok": 1}}`)
+			if err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			expected := `<svg width="100.000000" height="100.000000">` + tc.expected + `</svg>`
+			got := svg.Draw(100, 100,
+				geojson2svg.UseProperties([]string{"name", "bad key\"", "1st", "data-ok"}),
+				geojson2svg.WithKeyPolicy(tc.policy))
+			if got != expected {
+				tt.Errorf("expected %s\ngot %s", expected, got)
+			}
+		})
+	}
+}
+
+func TestAttributeRejectKeys(t *testing.T) {
+	svg := geojson2svg.New()
+	err := svg.AddFeature(`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0,0]},
+		"properties": {"on click": "alert(1)"}}`)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	err = svg.DrawTo(&bytes.Buffer{}, 100, 100,
+		geojson2svg.UseProperties([]string{"on click"}),
+		geojson2svg.WithKeyPolicy(geojson2svg.RejectKeys))
+	if err == nil || err.Error() != `invalid attribute name: "on click"` {
+		t.Errorf("expected invalid attribute name error, got %v", err)
+	}
+}
+
+func TestDrawDropsRejectedKeys(t *testing.T) {
+	svg := geojson2svg.New()
+	err := svg.AddFeature(`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0,0]},
+		"properties": {"on click": "alert(1)", "class": "a"}}`)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	expected := `<svg width="100.000000" height="100.000000"><circle cx="50.000000" cy="50.000000" r="1" class="a"/></svg>`
+	got := svg.Draw(100, 100,
+		geojson2svg.UseProperties([]string{"on click", "class"}),
+		geojson2svg.WithKeyPolicy(geojson2svg.RejectKeys))
+	if got != expected {
+		t.Errorf("expected %s\ngot %s", expected, got)
+	}
+}
+
+func TestRootAttributeEscaping(t *testing.T) {
+	svg := geojson2svg.New()
+	expected := `<svg width="100.000000" height="100.000000" data-title="&lt;/svg&gt;&lt;script&gt;"></svg>`
+	got := svg.Draw(100, 100,
+		geojson2svg.WithAttribute("data-title", "</svg><script>"),
+		geojson2svg.WithAttribute("x\"><script", "y"))
+	if got != expected {
+		t.Errorf("expected %s\ngot %s", expected, got)
+	}
+}
+
+func TestSanitizedKeyCollisions(t *testing.T) {
+	tcs := []struct {
+		name       string
+		properties string
+		keys       []string
+		expected   string
+	}{
+		{"valid key wins",
+			`{"a b": "x", "a_b": "y"}`,
+			[]string{"a b", "a_b"},
+			`a_b="y"`},
+		{"first sanitized key wins",
+			`{"a b": "x", "a\"b": "y"}`,
+			[]string{"a\"b", "a b"},
+			`a_b="x"`},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			svg := geojson2svg.New()
+			err := svg.AddFeature(`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0,0]},
+				"properties": ` + tc.properties + `}`)
+			if err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			expected := `<svg width="100.000000" height="100.000000"><circle cx="50.000000" cy="50.000000" r="1" ` + tc.expected + `/></svg>`
+			got := svg.Draw(100, 100,
+				geojson2svg.UseProperties(tc.keys),
+				geojson2svg.WithKeyPolicy(geojson2svg.SanitizeKeys))
+			if got != expected {
+				tt.Errorf("expected %s\ngot %s", expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package geojson2svg
+
+import geojson "github.com/paulmach/go.geojson"
+
+// clipMargin is the distance in pixels the geometries are clipped outside
+// of the visible extent, so the outlines created by clipping are hidden.
+const clipMargin = 8
+
+func (b box) contains(x, y float64) bool {
+	return x >= b.minX && x <= b.maxX && y >= b.minY && y <= b.maxY
+}
+
+// visible returns whether the bounding box of the scaled geometry overlaps
+// the clip box.
+func (d *drawer) visible(g *geojson.Geometry) bool {
+	if d.clip == nil {
+		return true
+	}
+	ps := collect(g)
+	if len(ps) == 0 {
+		return false
+	}
+	b := bounds(scaleLine(d.sf, ps))
+	return b.minX <= d.clip.maxX && b.maxX >= d.clip.minX && b.minY <= d.clip.maxY && b.maxY >= d.clip.minY
+}
+
+// clipLine clips the linestring to the box with the Liang–Barsky algorithm.
+// A linestring leaving and reentering the box is split into several parts.
+func clipLine(ps [][]float64, b box) [][][]float64 {
+	if len(ps) == 1 {
+		if b.contains(ps[0][0], ps[0][1]) {
+			return [][][]float64{ps}
+		}
+		return nil
+	}
+
+	parts := [][][]float64{}
+	var part [][]float64
+	for i := 1; i < len(ps); i++ {
+		p, q, ok := clipSegment(ps[i-1], ps[i], b)
+		if !ok {
+			continue
+		}
+		if len(part) == 0 || !equalPoints(part[len(part)-1], p) {
+			if len(part) > 0 {
+				parts = append(parts, part)
+			}
+			part = [][]float64{p}
+		}
+		part = append(part, q)
+	}
+	if len(part) > 0 {
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// clipSegment clips the segment from a to b with the Liang–Barsky
+// algorithm. It returns false if the segment is outside of the box.
+func clipSegment(a, b []float64, bx box) ([]float64, []float64, bool) {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	t0, t1 := 0.0, 1.0
+	edges := [4][2]float64{
+		{-dx, a[0] - bx.minX},
+		{dx, bx.maxX - a[0]},
+		{-dy, a[1] - bx.minY},
+		{dy, bx.maxY - a[1]},
+	}
+	for _, e := range edges {
+		p, q := e[0], e[1]
+		if p == 0 {
+			if q < 0 {
+				return nil, nil, false
+			}
+			continue
+		}
+		r := q / p
+		if p < 0 {
+			if r > t1 {
+				return nil, nil, false
+			}
+			if r > t0 {
+				t0 = r
+			}
+		} else {
+			if r < t0 {
+				return nil, nil, false
+			}
+			if r < t1 {
+				t1 = r
+			}
+		}
+	}
+	from, to := a, b
+	if t0 > 0 {
+		from = []float64{a[0] + t0*dx, a[1] + t0*dy}
+	}
+	if t1 < 1 {
+		to = []float64{a[0] + t1*dx, a[1] + t1*dy}
+	}
+	return from, to, true
+}
+
+// clipRing clips the ring to the box with the Sutherland–Hodgman algorithm.
+// An empty ring is returned if the ring is outside of the box.
+func clipRing(ps [][]float64, b box) [][]float64 {
+	edges := []struct {
+		inside    func(p []float64) bool
+		intersect func(p, q []float64) []float64
+	}{
+		{func(p []float64) bool { return p[0] >= b.minX }, func(p, q []float64) []float64 { return intersectX(p, q, b.minX) }},
+		{func(p []float64) bool { return p[0] <= b.maxX }, func(p, q []float64) []float64 { return intersectX(p, q, b.maxX) }},
+		{func(p []float64) bool { return p[1] >= b.minY }, func(p, q []float64) []float64 { return intersectY(p, q, b.minY) }},
+		{func(p []float64) bool { return p[1] <= b.maxY }, func(p, q []float64) []float64 { return intersectY(p, q, b.maxY) }},
+	}
+
+	res := ps
+	if len(res) > 1 && equalPoints(res[0], res[len(res)-1]) {
+		res = res[:len(res)-1]
+	}
+	for _, e := range edges {
+		in := res
+		res = nil
+		for i, p := range in {
+			prev := in[(i+len(in)-1)%len(in)]
+			switch {
+			case e.inside(p) && !e.inside(prev):
+				res = append(res, e.intersect(prev, p), p)
+			case e.inside(p):
+				res = append(res, p)
+			case e.inside(prev):
+				res = append(res, e.intersect(prev, p))
+			}
+		}
+	}
+	if len(res) < 3 {
+		return nil
+	}
+	// close the ring again
+	return append(res, res[0])
+}
+
+func intersectX(p, q []float64, x float64) []float64 {
+	return []float64{x, p[1] + (q[1]-p[1])*(x-p[0])/(q[0]-p[0])}
+}
+
+func intersectY(p, q []float64, y float64) []float64 {
+	return []float64{p[0] + (q[0]-p[0])*(y-p[1])/(q[1]-p[1]), y}
+}
+
+func equalPoints(p, q []float64) bool {
+	return p[0] == q[0] && p[1] == q[1]
+}
@@ -0,0 +1,80 @@
+package geojson2svg_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/fapian/geojson2svg/pkg/geojson2svg"
+)
+
+func TestClipping(t *testing.T) {
+	tcs := []struct {
+		name     string
+		geometry string
+		expected string
+	}{
+		{"linestring inside",
+			`{"type": "LineString", "coordinates": [[10,10], [90,90]]}`,
+			`<path d="M20.000000 180.000000,180.000000 20.000000"/>`},
+		{"linestring crossing",
+			`{"type": "LineString", "coordinates": [[-100,50], [200,50]]}`,
+			`<path d="M-8.000000 100.000000,208.000000 100.000000"/>`},
+		{"linestring leaving and reentering",
+			`{"type": "LineString", "coordinates": [[50,50], [50,200], [60,200], [60,50]]}`,
+			`<path d="M100.000000 100.000000,100.000000 -8.000000 M120.000000 -8.000000,120.000000 100.000000"/>`},
+		{"linestring outside",
+			`{"type": "LineString", "coordinates": [[-100,-100], [-50,-50]]}`,
+			``},
+		{"linestring with a bounding box overlapping",
+			`{"type": "LineString", "coordinates": [[-50,50], [-10,-50], [150,-50]]}`,
+			``},
+		{"points",
+			`{"type": "MultiPoint", "coordinates": [[50,50], [150,150]]}`,
+			`<circle cx="100.000000" cy="100.000000" r="1"/>`},
+		{"polygon crossing",
+			`{"type": "Polygon", "coordinates": [[[50,50], [150,50], [150,150], [50,150], [50,50]]]}`,
+			`<path d="M100.000000 -8.000000,100.000000 100.000000,208.000000 100.000000,208.000000 -8.000000,100.000000 -8.000000 Z"/>`},
+		{"polygon with holes",
+			`{"type": "Polygon", "coordinates": [
+				[[-50,-50], [150,-50], [150,150], [-50,150], [-50,-50]],
+				[[20,20], [40,20], [40,40], [20,40], [20,20]],
+				[[120,120], [140,120], [140,140], [120,140], [120,120]]
+			]}`,
+			`<path d="M-8.000000 -8.000000,-8.000000 208.000000,208.000000 208.000000,208.000000 -8.000000,-8.000000 -8.000000 M40.000000 160.000000,80.000000 160.000000,80.000000 120.000000,40.000000 120.000000,40.000000 160.000000 Z"/>`},
+		{"polygon outside",
+			`{"type": "Polygon", "coordinates": [[[150,150], [250,150], [250,250], [150,150]]]}`,
+			``},
+		{"multipolygon partially outside",
+			`{"type": "MultiPolygon", "coordinates": [
+				[[[150,150], [250,150], [250,250], [150,150]]],
+				[[[10,10], [20,10], [20,20], [10,10]]]
+			]}`,
+			`<path d="M20.000000 180.000000,40.000000 180.000000,40.000000 160.000000,20.000000 180.000000 Z"/>`},
+	}
+
+	re := regexp.MustCompile(`<g clip-path="url\(#bounds\)">(.*)</g>`)
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			svg := geojson2svg.New()
+			if err := svg.AddGeometry(tc.geometry); err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			got := re.FindStringSubmatch(svg.Draw(200, 200, geojson2svg.WithBounds(0, 0, 100, 100)))
+			if got == nil || got[1] != tc.expected {
+				tt.Errorf("expected %s, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNoClippingWithoutBounds(t *testing.T) {
+	want := `<svg width="200.000000" height="200.000000"><path d="M0.000000 200.000000,200.000000 0.000000"/></svg>`
+
+	svg := geojson2svg.New()
+	if err := svg.AddGeometry(`{"type": "LineString", "coordinates": [[-100,-100], [200,200]]}`); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got := svg.Draw(200, 200); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
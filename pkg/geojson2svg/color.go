@@ -0,0 +1,112 @@
+package geojson2svg
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var namedColors = map[string]color.RGBA{
+	"black":   {0x00, 0x00, 0x00, 0xff},
+	"silver":  {0xc0, 0xc0, 0xc0, 0xff},
+	"gray":    {0x80, 0x80, 0x80, 0xff},
+	"grey":    {0x80, 0x80, 0x80, 0xff},
+	"white":   {0xff, 0xff, 0xff, 0xff},
+	"maroon":  {0x80, 0x00, 0x00, 0xff},
+	"red":     {0xff, 0x00, 0x00, 0xff},
+	"purple":  {0x80, 0x00, 0x80, 0xff},
+	"fuchsia": {0xff, 0x00, 0xff, 0xff},
+	"green":   {0x00, 0x80, 0x00, 0xff},
+	"lime":    {0x00, 0xff, 0x00, 0xff},
+	"olive":   {0x80, 0x80, 0x00, 0xff},
+	"yellow":  {0xff, 0xff, 0x00, 0xff},
+	"navy":    {0x00, 0x00, 0x80, 0xff},
+	"blue":    {0x00, 0x00, 0xff, 0xff},
+	"teal":    {0x00, 0x80, 0x80, 0xff},
+	"aqua":    {0x00, 0xff, 0xff, 0xff},
+	"orange":  {0xff, 0xa5, 0x00, 0xff},
+}
+
+// parseColor parses the css color notations #rgb, #rrggbb, rgb(r, g, b)
+// and the basic color keywords.
+func parseColor(s string) (color.RGBA, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if c, ok := namedColors[s]; ok {
+		return c, true
+	}
+
+	if strings.HasPrefix(s, "#") {
+		hex := s[1:]
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		if len(hex) != 6 {
+			return color.RGBA{}, false
+		}
+		v, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil {
+			return color.RGBA{}, false
+		}
+		return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xff}, true
+	}
+
+	if strings.HasPrefix(s, "rgb(") && strings.HasSuffix(s, ")") {
+		parts := strings.Split(s[4:len(s)-1], ",")
+		if len(parts) != 3 {
+			return color.RGBA{}, false
+		}
+		var c [3]uint8
+		for i, p := range parts {
+			v, err := strconv.ParseUint(strings.TrimSpace(p), 10, 8)
+			if err != nil {
+				return color.RGBA{}, false
+			}
+			c[i] = uint8(v)
+		}
+		return color.RGBA{c[0], c[1], c[2], 0xff}, true
+	}
+
+	return color.RGBA{}, false
+}
+
+func formatColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// interpolateColor returns the color at t (0 <= t <= 1) of the ramp.
+// If the colors of the ramp can not be parsed the nearest color is used.
+func interpolateColor(ramp []string, t float64) string {
+	if len(ramp) == 1 {
+		return ramp[0]
+	}
+	t = clamp(t, 0, 1) * float64(len(ramp)-1)
+	i := int(math.Min(t, float64(len(ramp)-2)))
+	from, okFrom := parseColor(ramp[i])
+	to, okTo := parseColor(ramp[i+1])
+	f := t - float64(i)
+	if !okFrom || !okTo {
+		if f < 0.5 {
+			return ramp[i]
+		}
+		return ramp[i+1]
+	}
+	mix := func(a, b uint8) uint8 { return uint8(float64(a) + (float64(b)-float64(a))*f + 0.5) }
+	return formatColor(color.RGBA{mix(from.R, to.R), mix(from.G, to.G), mix(from.B, to.B), 0xff})
+}
+
+// Ramp returns n colors evenly interpolated between the colors from and to.
+// The colors have to be given as #rgb, #rrggbb, rgb(r, g, b) or as a basic
+// color keyword.
+func Ramp(from, to string, n int) []string {
+	res := make([]string, n)
+	for i := range res {
+		t := 0.0
+		if n > 1 {
+			t = float64(i) / float64(n-1)
+		}
+		res[i] = interpolateColor([]string{from, to}, t)
+	}
+	return res
+}
@@ -0,0 +1,107 @@
+package geojson2svg
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	geojson "github.com/paulmach/go.geojson"
+)
+
+// WithFeatureIDs configures the SVG to emit the id of every feature as the
+// id attribute of its element.
+func WithFeatureIDs() Option {
+	return func(svg *SVG) {
+		svg.featureIDs = true
+	}
+}
+
+// WithDataAttributes configures the SVG to emit the given properties of
+// every feature as data-* attributes, e.g. the property "Name" as
+// data-name. Of properties only differing in case the lowercase one is
+// emitted, else the first in sorted order. If props is empty all properties are emitted. Strings are
+// emitted as they are, all other values JSON encoded.
+func WithDataAttributes(props []string) Option {
+	return func(svg *SVG) {
+		svg.dataProp = func(prop string) bool {
+			if len(props) == 0 {
+				return true
+			}
+			for _, p := range props {
+				if p == prop {
+					return true
+				}
+			}
+			return false
+		}
+	}
+}
+
+// dataAttributes returns the attributes identifying the feature f.
+func (svg *SVG) dataAttributes(f *geojson.Feature) map[string]string {
+	attrs := make(map[string]string)
+	if svg.featureIDs && f.ID != nil {
+		attrs["id"] = dataValue(f.ID)
+	}
+	if svg.dataProp == nil {
+		return attrs
+	}
+	// Keys only differing in case map to the same attribute. The lowercase
+	// key wins, else the first one in sorted order.
+	keys := make([]string, 0, len(f.Properties))
+	for k := range f.Properties {
+		if svg.dataProp(k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		li, lj := keys[i] == strings.ToLower(keys[i]), keys[j] == strings.ToLower(keys[j])
+		if li != lj {
+			return li
+		}
+		return keys[i] < keys[j]
+	})
+	for _, k := range keys {
+		name := "data-" + strings.ToLower(k)
+		if _, ok := attrs[name]; !ok {
+			attrs[name] = dataValue(f.Properties[k])
+		}
+	}
+	return attrs
+}
+
+func dataValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(bs)
+}
+
+// drawFeature draws the geometry of a feature. The data attributes and the
+// title and description belong to the feature as a whole, so geometries
+// drawn as several elements and features with a title or description are
+// grouped and the group carries them.
+func (d *drawer) drawFeature(g *geojson.Geometry, attrs, data map[string]string, title, desc string) {
+	if len(data) == 0 && title == "" && desc == "" {
+		d.process(g, attrs)
+		return
+	}
+	if g == nil || !d.visible(g) {
+		return
+	}
+	if title != "" || desc != "" || g.IsMultiPoint() || g.IsMultiLineString() || g.IsMultiPolygon() || g.IsCollection() {
+		d.r.group(data, title, desc)
+		d.process(g, attrs)
+		d.r.end()
+		return
+	}
+	for k, v := range data {
+		attrs[k] = v
+	}
+	d.process(g, attrs)
+}
@@ -0,0 +1,59 @@
+package geojson2svg_test
+
+import (
+	"testing"
+
+	"github.com/fapian/geojson2svg/pkg/geojson2svg"
+)
+
+func TestDataAttributes(t *testing.T) {
+	tcs := []struct {
+		name     string
+		feature  string
+		opts     []geojson2svg.Option
+		expected string
+	}{
+		{"feature id",
+			`{"type": "Feature", "id": "de", "geometry": {"type": "Point", "coordinates": [0,0]}, "properties": {"class": "state"}}`,
+			[]geojson2svg.Option{geojson2svg.WithFeatureIDs()},
+			`<circle cx="50" cy="50" r="1" class="state" id="de"/>`},
+		{"numeric feature id",
+			`{"type": "Feature", "id": 7, "geometry": {"type": "Point", "coordinates": [0,0]}, "properties": {}}`,
+			[]geojson2svg.Option{geojson2svg.WithFeatureIDs()},
+			`<circle cx="50" cy="50" r="1" id="7"/>`},
+		{"selected properties",
+			`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0,0]}, "properties": {"Name": "Berlin", "population": 3500000, "tags": ["a", "b"]}}`,
+			[]geojson2svg.Option{geojson2svg.WithDataAttributes([]string{"Name", "tags"})},
+			`<circle cx="50" cy="50" r="1" data-name="Berlin" data-tags="[&#34;a&#34;,&#34;b&#34;]"/>`},
+		{"all properties",
+			`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0,0]}, "properties": {"population": 3500000, "capital": true, "info": {"a": 1}}}`,
+			[]geojson2svg.Option{geojson2svg.WithDataAttributes(nil)},
+			`<circle cx="50" cy="50" r="1" data-capital="true" data-info="{&#34;a&#34;:1}" data-population="3500000"/>`},
+		{"lowercase key wins",
+			`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0,0]}, "properties": {"Name": "A", "name": "b", "NAME": "C"}}`,
+			[]geojson2svg.Option{geojson2svg.WithDataAttributes(nil)},
+			`<circle cx="50" cy="50" r="1" data-name="b"/>`},
+		{"first key in sorted order wins",
+			`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0,0]}, "properties": {"nAme": "c", "Name": "A", "NAME": "B"}}`,
+			[]geojson2svg.Option{geojson2svg.WithDataAttributes(nil)},
+			`<circle cx="50" cy="50" r="1" data-name="B"/>`},
+		{"multi geometry is grouped",
+			`{"type": "Feature", "id": "p", "geometry": {"type": "MultiPoint", "coordinates": [[0,0], [0,0]]}, "properties": {"class": "city", "name": "x"}}`,
+			[]geojson2svg.Option{geojson2svg.WithFeatureIDs(), geojson2svg.WithDataAttributes([]string{"name"})},
+			`<g data-name="x" id="p"><circle cx="50" cy="50" r="1" class="city"/><circle cx="50" cy="50" r="1" class="city"/></g>`},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			svg := geojson2svg.New()
+			if err := svg.AddFeature(tc.feature); err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			expected := `<svg width="100" height="100">` + tc.expected + `</svg>`
+			got := svg.Draw(100, 100, append(tc.opts, geojson2svg.WithPrecision(0))...)
+			if got != expected {
+				tt.Errorf("expected %s\ngot %s", expected, got)
+			}
+		})
+	}
+}
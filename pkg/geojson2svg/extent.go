@@ -0,0 +1,99 @@
+package geojson2svg
+
+import "math"
+
+// WithBounds configures the SVG to draw the fixed extent from minX, minY to
+// maxX, maxY instead of fitting the svg to its geometries. The bounds are
+// given in the coordinates of the geometries, with a projection the
+// extent covers the projected corners. Everything outside the extent is
+// clipped.
+func WithBounds(minX, minY, maxX, maxY float64) Option {
+	return func(svg *SVG) {
+		svg.bounds = &box{minX, minY, maxX, maxY}
+		svg.center = nil
+		svg.tile = nil
+	}
+}
+
+// WithCenter configures the SVG to draw the extent centered on x, y at the
+// given zoom instead of fitting the svg to its geometries. At zoom z one
+// unit of the (projected) coordinates spans 2^z pixels. Everything outside
+// the extent is clipped.
+func WithCenter(x, y, zoom float64) Option {
+	return func(svg *SVG) {
+		svg.center = &[3]float64{x, y, zoom}
+		svg.bounds = nil
+		svg.tile = nil
+	}
+}
+
+// WithBoundsBuffer configures the SVG to grow the extent by the given
+// percentage of its width and height on every side.
+func WithBoundsBuffer(percent float64) Option {
+	return func(svg *SVG) {
+		svg.buffer = percent
+	}
+}
+
+// extent returns the projected extent which is drawn into the svg.
+// It returns false if there is nothing to draw.
+func (svg *SVG) extent(width, height float64) (box, bool) {
+	var b box
+	switch {
+	case svg.bounds != nil:
+		ps := [][]float64{
+			{svg.bounds.minX, svg.bounds.minY},
+			{svg.bounds.minX, svg.bounds.maxY},
+			{svg.bounds.maxX, svg.bounds.minY},
+			{svg.bounds.maxX, svg.bounds.maxY},
+		}
+		b = bounds(svg.project(ps))
+	case svg.center != nil:
+		x, y := svg.center[0], svg.center[1]
+		if svg.projection != nil {
+			x, y = svg.projection.Project(x, y)
+		}
+		scale := math.Pow(2, svg.center[2])
+		w := (width - svg.padding.Left - svg.padding.Right) / scale / 2
+		h := (height - svg.padding.Top - svg.padding.Bottom) / scale / 2
+		b = box{x - w, y - h, x + w, y + h}
+	case svg.projection == nil:
+		// the index already knows the bounds of the unprojected data
+		var ok bool
+		if b, ok = svg.dataBounds(); !ok {
+			return box{}, false
+		}
+	default:
+		ps := svg.points()
+		if len(ps) == 0 {
+			return box{}, false
+		}
+		b = bounds(svg.project(ps))
+	}
+
+	if svg.buffer != 0 {
+		dx := (b.maxX - b.minX) * svg.buffer / 100
+		dy := (b.maxY - b.minY) * svg.buffer / 100
+		b = box{b.minX - dx, b.minY - dy, b.maxX + dx, b.maxY + dy}
+	}
+	return b, true
+}
+
+func (svg *SVG) project(ps [][]float64) [][]float64 {
+	if svg.projection == nil {
+		return ps
+	}
+	return projectPoints(svg.projection, ps)
+}
+
+// bounds returns the bounding box of the points.
+func bounds(ps [][]float64) box {
+	b := box{ps[0][0], ps[0][1], ps[0][0], ps[0][1]}
+	for _, p := range ps[1:] {
+		b.minX = math.Min(b.minX, p[0])
+		b.minY = math.Min(b.minY, p[1])
+		b.maxX = math.Max(b.maxX, p[0])
+		b.maxY = math.Max(b.maxY, p[1])
+	}
+	return b
+}
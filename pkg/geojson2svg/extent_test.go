@@ -0,0 +1,66 @@
+package geojson2svg_test
+
+import (
+	"testing"
+
+	"github.com/fapian/geojson2svg/pkg/geojson2svg"
+)
+
+func TestExtentOptions(t *testing.T) {
+	const clip = `<defs><clipPath id="bounds"><rect x="0.000000" y="0.000000" width="200.000000" height="200.000000"/></clipPath></defs>`
+	tcs := []struct {
+		name     string
+		geometry string
+		opts     []geojson2svg.Option
+		expected string
+	}{
+		{"with bounds",
+			`{"type": "LineString", "coordinates": [[50,50], [150,150]]}`,
+			[]geojson2svg.Option{geojson2svg.WithBounds(0, 0, 100, 100)},
+			`<svg width="200.000000" height="200.000000">` + clip +
+				`<g clip-path="url(#bounds)"><path d="M100.000000 100.000000,208.000000 -8.000000"/></g></svg>`},
+		{"with bounds and padding",
+			`{"type": "LineString", "coordinates": [[50,50], [150,150]]}`,
+			[]geojson2svg.Option{geojson2svg.WithBounds(0, 0, 100, 100), geojson2svg.WithPadding(geojson2svg.Padding{Top: 10, Right: 10, Bottom: 10, Left: 10})},
+			`<svg width="200.000000" height="200.000000">` +
+				`<defs><clipPath id="bounds"><rect x="10.000000" y="10.000000" width="180.000000" height="180.000000"/></clipPath></defs>` +
+				`<g clip-path="url(#bounds)"><path d="M100.000000 100.000000,198.000000 2.000000"/></g></svg>`},
+		{"with center",
+			`{"type": "LineString", "coordinates": [[50,50], [150,150]]}`,
+			[]geojson2svg.Option{geojson2svg.WithCenter(50, 50, 1)},
+			`<svg width="200.000000" height="200.000000">` + clip +
+				`<g clip-path="url(#bounds)"><path d="M100.000000 100.000000,208.000000 -8.000000"/></g></svg>`},
+		{"last extent wins",
+			`{"type": "LineString", "coordinates": [[50,50], [150,150]]}`,
+			[]geojson2svg.Option{geojson2svg.WithCenter(0, 0, 10), geojson2svg.WithBounds(0, 0, 100, 100)},
+			`<svg width="200.000000" height="200.000000">` + clip +
+				`<g clip-path="url(#bounds)"><path d="M100.000000 100.000000,208.000000 -8.000000"/></g></svg>`},
+		{"with buffer",
+			`{"type": "LineString", "coordinates": [[0,0], [100,100]]}`,
+			[]geojson2svg.Option{geojson2svg.WithBoundsBuffer(10)},
+			`<svg width="200.000000" height="200.000000"><path d="M16.666667 183.333333,183.333333 16.666667"/></svg>`},
+		{"with bounds and buffer",
+			`{"type": "LineString", "coordinates": [[0,0], [100,100]]}`,
+			[]geojson2svg.Option{geojson2svg.WithBounds(10, 10, 90, 90), geojson2svg.WithBoundsBuffer(25)},
+			`<svg width="200.000000" height="200.000000">` + clip +
+				`<g clip-path="url(#bounds)"><path d="M16.666667 183.333333,183.333333 16.666667"/></g></svg>`},
+		{"with projected bounds",
+			`{"type": "Point", "coordinates": [0,0]}`,
+			[]geojson2svg.Option{geojson2svg.WithProjection(geojson2svg.WebMercator()), geojson2svg.WithBounds(-180, -85.0511287798066, 180, 85.0511287798066)},
+			`<svg width="200.000000" height="200.000000">` + clip +
+				`<g clip-path="url(#bounds)"><circle cx="100.000000" cy="100.000000" r="1"/></g></svg>`},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			svg := geojson2svg.New()
+			if err := svg.AddGeometry(tc.geometry); err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			got := svg.Draw(200, 200, tc.opts...)
+			if got != tc.expected {
+				tt.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
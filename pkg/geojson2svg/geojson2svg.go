@@ -5,12 +5,15 @@
 package geojson2svg
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math"
-	"regexp"
-	"sort"
+	"strconv"
 	"strings"
 
 	geojson "github.com/paulmach/go.geojson"
@@ -28,9 +31,71 @@ type scaleFunc func(float64, float64) (float64, float64)
 // default properties (class)
 //
 // default attributes ()
+//
+// default data attributes (none, no feature ids)
+//
+// default projection (none, coordinates are used as planar coordinates)
+//
+// default style (none)
+//
+// default legend (none)
+//
+// default labels (none)
+//
+// default points (circles with radius 1)
+//
+// default extent (fit to all geometries)
+//
+// default simplification (none)
+//
+// default number format (six decimals, absolute paths)
+//
+// default key policy (drop invalid attribute names)
+//
+// default stylesheet (none, no automatic classes)
+//
+// default accessibility metadata (none)
+//
+// default layers (none)
+//
+// default root element (fixed width and height, no viewBox)
+//
+// default id prefix (the id attribute of the root followed by "-", if set)
 type SVG struct {
 	useProp            func(string) bool
+	dataProp           func(string) bool
+	featureIDs         bool
 	padding            Padding
+	projection         Projection
+	style              *Style
+	legend             *Legend
+	labels             *labels
+	pointStyle         *PointStyle
+	bounds             *box
+	center             *[3]float64
+	buffer             float64
+	simplifier         Simplifier
+	tolerance          float64
+	format             formatter
+	keyPolicy          KeyPolicy
+	viewBox            bool
+	aspectRatio        string
+	dimensions         *[2]string
+	stylesheet         string
+	styleRules         []StyleRule
+	autoClasses        bool
+	classProps         []string
+	title              string
+	description        string
+	featureTitle       *featureTemplate
+	featureDescription *featureTemplate
+	id                 string
+	zIndex             int
+	layers             []*SVG
+	idPrefix           string
+	tile               *tile
+	index              *featureIndex
+	version            int
 	attributes         map[string]string
 	geometries         []*geojson.Geometry
 	features           []*geojson.Feature
@@ -40,6 +105,30 @@ type SVG struct {
 // Padding represents the possible padding of the SVG.
 type Padding struct{ Top, Right, Bottom, Left float64 }
 
+// ParsePadding parses a padding given as 'all', 'vertical,horizontal' or
+// 'top,right,bottom,left' like CSS. The values must be finite and not
+// negative.
+func ParsePadding(s string) (Padding, error) {
+	parts := strings.Split(s, ",")
+	vs := make([]float64, len(parts))
+	for i, x := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) || v < 0 {
+			return Padding{}, fmt.Errorf("invalid padding: %s", s)
+		}
+		vs[i] = v
+	}
+	switch len(vs) {
+	case 1:
+		return Padding{Top: vs[0], Right: vs[0], Bottom: vs[0], Left: vs[0]}, nil
+	case 2:
+		return Padding{Top: vs[0], Right: vs[1], Bottom: vs[0], Left: vs[1]}, nil
+	case 4:
+		return Padding{Top: vs[0], Right: vs[1], Bottom: vs[2], Left: vs[3]}, nil
+	}
+	return Padding{}, fmt.Errorf("invalid padding: %s", s)
+}
+
 // An Option represents a single SVG option.
 type Option func(*SVG)
 
@@ -49,35 +138,132 @@ func New() *SVG {
 	return &SVG{
 		useProp:    func(prop string) bool { return prop == "class" },
 		attributes: make(map[string]string),
+		format:     defaultFormatter,
+		index:      &featureIndex{},
 	}
 }
 
 // Draw renders the final SVG with the given options to a string.
 // All coordinates will be scaled to fit into the svg.
+// Attribute names rejected by RejectKeys are dropped instead. If the svg
+// can not be drawn, e.g. because of an invalid feature template, the
+// string is empty or partial. Use DrawTo to detect these errors.
 func (svg *SVG) Draw(width, height float64, opts ...Option) string {
+	if err := svg.apply(opts); err != nil {
+		return ""
+	}
+	policy := svg.keyPolicy
+	if policy == RejectKeys {
+		policy = DropKeys
+	}
+	res := bytes.NewBufferString("")
+	// writing to a bytes.Buffer never fails
+	_ = svg.drawTo(res, width, height, policy)
+	return res.String()
+}
+
+// DrawTo renders the final SVG with the given options to w.
+// The elements are streamed to w as they are produced, so the SVG is never
+// held in memory as a whole. The first error returned by w is returned.
+// With RejectKeys drawing stops at the first invalid attribute name and
+// an error is returned, the partial SVG written to w must be discarded.
+func (svg *SVG) DrawTo(w io.Writer, width, height float64, opts ...Option) error {
+	if err := svg.apply(opts); err != nil {
+		return err
+	}
+	return svg.drawTo(w, width, height, svg.keyPolicy)
+}
+
+// drawTo renders the svg to w, invalid attribute names are handled
+// according to policy.
+func (svg *SVG) drawTo(w io.Writer, width, height float64, policy KeyPolicy) error {
+	bw := bufio.NewWriter(w)
+	r := &svgRenderer{
+		w:          &errWriter{w: bw},
+		format:     svg.format,
+		attributes: &attributeEncoder{policy: policy},
+		prefix:     svg.prefix(),
+	}
+	fmt.Fprintf(r.w, `<svg%s>`, svg.rootAttributes(r.attributes, width, height))
+	svg.drawMetadata(r.w)
+	if err := svg.render(r, width, height); err != nil {
+		return err
+	}
+	fmt.Fprint(r.w, `</svg>`)
+
+	if err := r.err(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// apply applies the options to the svg and returns the first error of an
+// option.
+func (svg *SVG) apply(opts []Option) error {
 	for _, o := range opts {
 		o(svg)
 	}
+	return svg.templateErr()
+}
 
-	sf := makeScaleFunc(width, height, svg.padding, svg.points())
+// render draws the content of the svg, its labels and its legend into a
+// canvas of the given size with r.
+func (svg *SVG) render(r renderer, width, height float64) error {
+	var st *styling
+	if svg.style != nil {
+		st = newStyling(*svg.style, svg.ownFeatures())
+	}
 
-	content := bytes.NewBufferString("")
-	for _, g := range svg.geometries {
-		process(sf, content, g, "")
+	d := svg.newDrawer(r, width, height)
+	r.defs(svg.css(), d.points)
+	if d.clip != nil {
+		m := svg.clipMargin()
+		r.clip(d.clip.minX+m, d.clip.minY+m, d.clip.maxX-d.clip.minX-2*m, d.clip.maxY-d.clip.minY-2*m)
 	}
-	for _, f := range svg.features {
-		as := makeAttributesFromProperties(svg.useProp, f.Properties)
-		process(sf, content, f.Geometry, as)
+	if err := svg.drawContent(d, st); err != nil {
+		return err
 	}
-	for _, fc := range svg.featureCollections {
-		for _, f := range fc.Features {
-			as := makeAttributesFromProperties(svg.useProp, f.Properties)
-			process(sf, content, f.Geometry, as)
-		}
+	if svg.labels != nil {
+		drawLabels(r, d.sf, svg.labels, svg.allFeatures(), width, height)
+	}
+	if d.clip != nil {
+		r.end()
+	}
+	if svg.legend != nil && st != nil && !st.empty() {
+		r.legend(*svg.legend, st, width, height)
 	}
+	return r.err()
+}
 
-	attributes := makeAttributes(svg.attributes)
-	return fmt.Sprintf(`<svg width="%f" height="%f"%s>%s</svg>`, width, height, attributes, content)
+// newDrawer returns a drawer which scales the geometries of the svg into a
+// canvas of the given size and draws them with r.
+func (svg *SVG) newDrawer(r renderer, width, height float64) *drawer {
+	prefix := svg.prefix()
+	d := &drawer{r: r, prefix: prefix, points: newPointStyling(svg.pointStyle, svg.ownFeatures(), prefix+pointSymbolID)}
+	sf := func(x, y float64) (float64, float64) { return x, y }
+	b, ok := svg.extent(width, height)
+	if ok {
+		sf = makeScaleFunc(width, height, svg.padding, b)
+	}
+	if ok && (svg.bounds != nil || svg.center != nil) {
+		m := svg.clipMargin()
+		x0, y0 := sf(b.minX, b.maxY)
+		x1, y1 := sf(b.maxX, b.minY)
+		d.clip = &box{x0 - m, y0 - m, x1 + m, y1 + m}
+		if x1 > x0 {
+			d.query = svg.query(b, m*(b.maxX-b.minX)/(x1-x0))
+		}
+	}
+	if svg.projection != nil {
+		sf = projectScaleFunc(svg.projection, sf)
+	}
+	d.sf = sf
+	if svg.simplifier != nil {
+		d.simplifier = svg.simplifier
+		d.tolerance = svg.tolerance
+		d.topology = newTopology(svg.allGeometries())
+	}
+	return d
 }
 
 // AddGeometry adds a geojson geometry to the svg.
@@ -87,6 +273,7 @@ func (svg *SVG) AddGeometry(gs string) error {
 		return fmt.Errorf("invalid geometry: %s", gs)
 	}
 	svg.geometries = append(svg.geometries, g)
+	svg.version++
 	return nil
 }
 
@@ -97,6 +284,7 @@ func (svg *SVG) AddFeature(fs string) error {
 		return fmt.Errorf("invalid feature: %s", fs)
 	}
 	svg.features = append(svg.features, f)
+	svg.version++
 	return nil
 }
 
@@ -107,6 +295,109 @@ func (svg *SVG) AddFeatureCollection(fcs string) error {
 		return fmt.Errorf("invalid feature collection: %s", fcs)
 	}
 	svg.featureCollections = append(svg.featureCollections, fc)
+	svg.version++
+	return nil
+}
+
+// AddGeometryObject adds an already parsed geojson geometry to the svg.
+// A nil geometry is ignored.
+func (svg *SVG) AddGeometryObject(g *geojson.Geometry) {
+	if g != nil {
+		svg.geometries = append(svg.geometries, g)
+		svg.version++
+	}
+}
+
+// AddFeatureObject adds an already parsed geojson feature to the svg.
+// A nil feature is ignored.
+func (svg *SVG) AddFeatureObject(f *geojson.Feature) {
+	if f != nil {
+		svg.features = append(svg.features, f)
+		svg.version++
+	}
+}
+
+// AddFeatureCollectionObject adds an already parsed geojson
+// featurecollection to the svg. A nil featurecollection is ignored.
+func (svg *SVG) AddFeatureCollectionObject(fc *geojson.FeatureCollection) {
+	if fc != nil {
+		svg.featureCollections = append(svg.featureCollections, fc)
+		svg.version++
+	}
+}
+
+// AddFromReader reads a geojson geometry, feature or featurecollection
+// from r and adds it to the svg. The type of the object is detected from
+// its type member. Positions with less than two coordinates are
+// rejected, so untrusted input can be drawn safely.
+func (svg *SVG) AddFromReader(r io.Reader) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	var object struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return fmt.Errorf("invalid geojson: %v", err)
+	}
+
+	switch object.Type {
+	case "FeatureCollection":
+		fc, err := geojson.UnmarshalFeatureCollection(data)
+		if err != nil {
+			return fmt.Errorf("invalid feature collection: %v", err)
+		}
+		for _, f := range fc.Features {
+			if err := validateGeometry(f.Geometry); err != nil {
+				return fmt.Errorf("invalid feature collection: %v", err)
+			}
+		}
+		svg.AddFeatureCollectionObject(fc)
+	case "Feature":
+		f, err := geojson.UnmarshalFeature(data)
+		if err != nil {
+			return fmt.Errorf("invalid feature: %v", err)
+		}
+		if err := validateGeometry(f.Geometry); err != nil {
+			return fmt.Errorf("invalid feature: %v", err)
+		}
+		svg.AddFeatureObject(f)
+	case "Point", "MultiPoint", "LineString", "MultiLineString", "Polygon", "MultiPolygon", "GeometryCollection":
+		g, err := geojson.UnmarshalGeometry(data)
+		if err != nil {
+			return fmt.Errorf("invalid geometry: %v", err)
+		}
+		if err := validateGeometry(g); err != nil {
+			return fmt.Errorf("invalid geometry: %v", err)
+		}
+		svg.AddGeometryObject(g)
+	default:
+		return fmt.Errorf("invalid geojson type: %q", object.Type)
+	}
+	return nil
+}
+
+// validateGeometry returns an error if a position of the geometry has less
+// than two coordinates.
+func validateGeometry(g *geojson.Geometry) error {
+	if g == nil {
+		return nil
+	}
+	if g.IsCollection() {
+		for _, x := range g.Geometries {
+			if err := validateGeometry(x); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, p := range collect(g) {
+		if len(p) < 2 {
+			return errors.New("position with less than two coordinates")
+		}
+	}
 	return nil
 }
 
@@ -135,6 +426,76 @@ func WithPadding(p Padding) Option {
 	}
 }
 
+// WithViewBox configures the SVG to emit a viewBox covering the drawing,
+// so the svg can be scaled by its container.
+func WithViewBox() Option {
+	return func(svg *SVG) {
+		svg.viewBox = true
+	}
+}
+
+// WithPreserveAspectRatio configures the SVG to emit the given
+// preserveAspectRatio attribute (e.g. "xMidYMid meet") together with a
+// viewBox.
+func WithPreserveAspectRatio(v string) Option {
+	return func(svg *SVG) {
+		svg.viewBox = true
+		svg.aspectRatio = v
+	}
+}
+
+// WithDimensions overrides the width and height attributes of the svg root
+// element, e.g. with percentages. An empty value omits the attribute. The
+// width and height passed to Draw still define the coordinate system.
+func WithDimensions(width, height string) Option {
+	return func(svg *SVG) {
+		svg.dimensions = &[2]string{width, height}
+	}
+}
+
+// Responsive configures the SVG to emit a viewBox, a width of 100% and no
+// height, so the svg fills the width of its container and keeps its aspect
+// ratio.
+func Responsive() Option {
+	return func(svg *SVG) {
+		WithViewBox()(svg)
+		WithDimensions("100%", "")(svg)
+	}
+}
+
+// WithIDPrefix configures the prefix of the ids of the elements the SVG
+// defines, e.g. its clip path and its legend gradient, so several svgs can
+// be inlined into one HTML document. By default the ids are prefixed with
+// the id attribute of the root followed by "-", if it is set.
+func WithIDPrefix(prefix string) Option {
+	return func(svg *SVG) {
+		svg.idPrefix = prefix
+	}
+}
+
+// prefix returns the prefix of the ids of the elements.
+func (svg *SVG) prefix() string {
+	if svg.idPrefix != "" {
+		return svg.idPrefix
+	}
+	if id := svg.attributes["id"]; id != "" {
+		return id + "-"
+	}
+	return ""
+}
+
+// ParseProperties parses a comma separated list of property names, the
+// names are trimmed and empty names are left out.
+func ParseProperties(s string) []string {
+	res := []string{}
+	for _, x := range strings.Split(s, ",") {
+		if x = strings.TrimSpace(x); x != "" {
+			res = append(res, x)
+		}
+	}
+	return res
+}
+
 // UseProperties configures which geojson properties should be copied to the
 // resulting SVG element.
 func UseProperties(props []string) Option {
@@ -152,42 +513,143 @@ func UseProperties(props []string) Option {
 
 func (svg *SVG) points() [][]float64 {
 	ps := [][]float64{}
-	for _, g := range svg.geometries {
+	for _, g := range svg.allGeometries() {
 		ps = append(ps, collect(g)...)
 	}
-	for _, f := range svg.features {
-		ps = append(ps, collect(f.Geometry)...)
+	return ps
+}
+
+func (svg *SVG) rootAttributes(ae *attributeEncoder, width, height float64) string {
+	res := bytes.NewBufferString("")
+	f := svg.format
+	if svg.dimensions == nil {
+		fmt.Fprintf(res, ` width="%s" height="%s"`, f.number(width), f.number(height))
+	} else {
+		if svg.dimensions[0] != "" {
+			fmt.Fprintf(res, ` width="%s"`, escapeText(svg.dimensions[0]))
+		}
+		if svg.dimensions[1] != "" {
+			fmt.Fprintf(res, ` height="%s"`, escapeText(svg.dimensions[1]))
+		}
+	}
+	if svg.viewBox {
+		fmt.Fprintf(res, ` viewBox="0 0 %s %s"`, f.number(width), f.number(height))
 	}
-	for _, fs := range svg.featureCollections {
-		for _, f := range fs.Features {
-			ps = append(ps, collect(f.Geometry)...)
+	if svg.aspectRatio != "" {
+		fmt.Fprintf(res, ` preserveAspectRatio="%s"`, escapeText(svg.aspectRatio))
+	}
+	if _, ok := svg.attributes["xmlns:xlink"]; !ok && svg.usesLinks() {
+		fmt.Fprintf(res, ` xmlns:xlink="%s"`, xlinkNamespace)
+	}
+	res.WriteString(svg.accessibilityAttributes())
+	res.WriteString(ae.encode(svg.attributes))
+	return res.String()
+}
+
+// xlinkNamespace is the namespace of the xlink:href attributes, which SVG
+// 1.1 renderers need besides the href attributes of SVG 2.
+const xlinkNamespace = "http://www.w3.org/1999/xlink"
+
+// usesLinks returns whether the svg or its layers reference point symbols
+// or label paths.
+func (svg *SVG) usesLinks() bool {
+	if svg.pointStyle != nil && svg.pointStyle.Symbol != "" || svg.labels != nil && svg.labels.alongLines {
+		return true
+	}
+	for _, l := range svg.layers {
+		if l.usesLinks() {
+			return true
 		}
 	}
-	return ps
+	return false
 }
 
-func process(sf scaleFunc, w io.Writer, g *geojson.Geometry, attributes string) {
+// allGeometries returns the geometries of the svg, its features and its
+// layers.
+func (svg *SVG) allGeometries() []*geojson.Geometry {
+	gs := append([]*geojson.Geometry{}, svg.geometries...)
+	for _, f := range svg.ownFeatures() {
+		gs = append(gs, f.Geometry)
+	}
+	for _, l := range svg.layers {
+		gs = append(gs, l.allGeometries()...)
+	}
+	return gs
+}
+
+// allFeatures returns the features of the svg and its layers.
+func (svg *SVG) allFeatures() []*geojson.Feature {
+	fs := svg.ownFeatures()
+	for _, l := range svg.layers {
+		fs = append(fs, l.allFeatures()...)
+	}
+	return fs
+}
+
+// ownFeatures returns the features of the svg without its layers.
+func (svg *SVG) ownFeatures() []*geojson.Feature {
+	fs := append([]*geojson.Feature{}, svg.features...)
+	for _, fc := range svg.featureCollections {
+		fs = append(fs, fc.Features...)
+	}
+	return fs
+}
+
+func (svg *SVG) featureAttributes(st *styling, f *geojson.Feature) map[string]string {
+	attrs := attributesFromProperties(svg.useProp, f.Properties)
+	st.apply(attrs, f.Properties)
+	svg.applyClasses(attrs, f.Geometry, f.Properties)
+	return attrs
+}
+
+// drawer draws geometries scaled into the coordinate system of the svg
+// with a renderer.
+// If clip is set the geometries are clipped to it, if query is set only
+// the geometries and features intersecting it are drawn.
+// If a simplifier is set the lines are simplified after scaling.
+// Points are drawn with the radius of the current feature.
+// The ids of the elements start with prefix.
+type drawer struct {
+	sf         scaleFunc
+	r          renderer
+	clip       *box
+	query      *box
+	simplifier Simplifier
+	tolerance  float64
+	topology   topology
+	points     *pointStyling
+	radius     float64
+	prefix     string
+}
+
+func (d *drawer) process(g *geojson.Geometry, attrs map[string]string) {
+	if g == nil || !d.visible(g) {
+		return
+	}
 	switch {
 	case g.IsPoint():
-		drawPoint(sf, w, g.Point, attributes)
+		d.drawPoint(g.Point, attrs)
 	case g.IsMultiPoint():
-		drawMultiPoint(sf, w, g.MultiPoint, attributes)
+		d.drawMultiPoint(g.MultiPoint, attrs)
 	case g.IsLineString():
-		drawLineString(sf, w, g.LineString, attributes)
+		d.drawLineString(g.LineString, attrs)
 	case g.IsMultiLineString():
-		drawMultiLineString(sf, w, g.MultiLineString, attributes)
+		d.drawMultiLineString(g.MultiLineString, attrs)
 	case g.IsPolygon():
-		drawPolygon(sf, w, g.Polygon, attributes)
+		d.drawPolygon(g.Polygon, attrs)
 	case g.IsMultiPolygon():
-		drawMultiPolygon(sf, w, g.MultiPolygon, attributes)
+		d.drawMultiPolygon(g.MultiPolygon, attrs)
 	case g.IsCollection():
 		for _, x := range g.Geometries {
-			process(sf, w, x, attributes)
+			d.process(x, attrs)
 		}
 	}
 }
 
 func collect(g *geojson.Geometry) (ps [][]float64) {
+	if g == nil {
+		return nil
+	}
 	switch {
 	case g.IsPoint():
 		ps = append(ps, g.Point)
@@ -217,106 +679,121 @@ func collect(g *geojson.Geometry) (ps [][]float64) {
 	return ps
 }
 
-func drawPoint(sf scaleFunc, w io.Writer, p []float64, attributes string) {
-	x, y := sf(p[0], p[1])
-	fmt.Fprintf(w, `<circle cx="%f" cy="%f" r="1"%s/>`, x, y, attributes)
+func (d *drawer) drawPoint(p []float64, attrs map[string]string) {
+	if d.radius <= 0 {
+		return
+	}
+	x, y := d.sf(p[0], p[1])
+	if d.clip != nil && !(box{x - d.radius, y - d.radius, x + d.radius, y + d.radius}).intersects(*d.clip) {
+		return
+	}
+	d.points.shape(d.r, x, y, d.radius, attrs)
 }
 
-func drawMultiPoint(sf scaleFunc, w io.Writer, ps [][]float64, attributes string) {
+func (d *drawer) drawMultiPoint(ps [][]float64, attrs map[string]string) {
 	for _, p := range ps {
-		drawPoint(sf, w, p, attributes)
+		d.drawPoint(p, attrs)
 	}
 }
 
-func drawLineString(sf scaleFunc, w io.Writer, ps [][]float64, attributes string) {
-	path := bytes.NewBufferString("M")
-	for _, p := range ps {
-		x, y := sf(p[0], p[1])
-		fmt.Fprintf(path, "%f %f,", x, y)
+func (d *drawer) drawLineString(ps [][]float64, attrs map[string]string) {
+	if len(ps) == 0 {
+		return
+	}
+	parts := [][][]float64{d.scale(ps, false)}
+	if d.clip != nil {
+		parts = clipLine(parts[0], *d.clip)
+	}
+	if len(parts) == 0 {
+		return
 	}
-	fmt.Fprintf(w, `<path d="%s"%s/>`, trim(path), attributes)
+	d.r.path(parts, false, attrs)
 }
 
-func drawMultiLineString(sf scaleFunc, w io.Writer, pps [][][]float64, attributes string) {
+func (d *drawer) drawMultiLineString(pps [][][]float64, attrs map[string]string) {
 	for _, ps := range pps {
-		drawLineString(sf, w, ps, attributes)
+		d.drawLineString(ps, attrs)
 	}
 }
 
-func drawPolygon(sf scaleFunc, w io.Writer, pps [][][]float64, attributes string) {
-	path := bytes.NewBufferString("")
-	for _, ps := range pps {
-		subPath := bytes.NewBufferString("M")
-		for _, p := range ps {
-			x, y := sf(p[0], p[1])
-			fmt.Fprintf(subPath, "%f %f,", x, y)
+func (d *drawer) drawPolygon(pps [][][]float64, attrs map[string]string) {
+	rings := [][][]float64{}
+	for i, ps := range pps {
+		ring := d.scale(ps, true)
+		if len(ring) < 4 && d.simplifier != nil {
+			// the ring collapsed
+			ring = nil
+		}
+		if d.clip != nil && len(ring) > 0 {
+			ring = clipRing(ring, *d.clip)
 		}
-		fmt.Fprintf(path, " %s", trim(subPath))
+		if len(ring) == 0 {
+			if i == 0 {
+				return
+			}
+			continue
+		}
+		rings = append(rings, ring)
+	}
+	if len(rings) == 0 {
+		return
 	}
-	fmt.Fprintf(w, `<path d="%s Z"%s/>`, trim(path), attributes)
+	d.r.path(rings, true, attrs)
 }
 
-func drawMultiPolygon(sf scaleFunc, w io.Writer, ppps [][][][]float64, attributes string) {
+// scale scales the line into the svg and simplifies it.
+func (d *drawer) scale(ps [][]float64, closed bool) [][]float64 {
+	if d.simplifier == nil {
+		return scaleLine(d.sf, ps)
+	}
+	return d.simplify(ps, closed)
+}
+
+func (d *drawer) drawMultiPolygon(ppps [][][][]float64, attrs map[string]string) {
 	for _, pps := range ppps {
-		drawPolygon(sf, w, pps, attributes)
+		d.drawPolygon(pps, attrs)
 	}
 }
 
-func trim(s fmt.Stringer) string {
-	re := regexp.MustCompile(",$")
-	return string(re.ReplaceAll([]byte(strings.TrimSpace(s.String())), []byte("")))
+// errWriter remembers the first error of the underlying writer and
+// discards all writes after it.
+type errWriter struct {
+	w   io.Writer
+	err error
 }
 
-func makeAttributes(as map[string]string) string {
-	keys := make([]string, 0, len(as))
-	for k := range as {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-	res := bytes.NewBufferString("")
-	for _, k := range keys {
-		fmt.Fprintf(res, ` %s="%s"`, k, as[k])
+func (ew *errWriter) Write(p []byte) (int, error) {
+	if ew.err != nil {
+		return 0, ew.err
 	}
-	return res.String()
+	n, err := ew.w.Write(p)
+	ew.err = err
+	return n, err
 }
 
-func makeAttributesFromProperties(useProp func(string) bool, props map[string]interface{}) string {
+func attributesFromProperties(useProp func(string) bool, props map[string]interface{}) map[string]string {
 	attrs := make(map[string]string)
 	for k, v := range props {
 		if useProp(k) {
 			attrs[k] = fmt.Sprintf("%v", v)
 		}
 	}
-	return makeAttributes(attrs)
+	return attrs
 }
 
-func makeScaleFunc(width, height float64, padding Padding, ps [][]float64) scaleFunc {
+func makeScaleFunc(width, height float64, padding Padding, b box) scaleFunc {
 	w := width - padding.Left - padding.Right
-	h := width - padding.Top - padding.Bottom
+	h := height - padding.Top - padding.Bottom
 
-	if len(ps) == 0 {
-		return func(x, y float64) (float64, float64) { return x, y }
+	if b.minX == b.maxX && b.minY == b.maxY {
+		return func(x, y float64) (float64, float64) { return padding.Left + w/2, padding.Top + h/2 }
 	}
 
-	if len(ps) == 1 {
-		return func(x, y float64) (float64, float64) { return w / 2, h / 2 }
-	}
-
-	minX := ps[0][0]
-	maxX := ps[0][0]
-	minY := ps[0][1]
-	maxY := ps[0][1]
-	for _, p := range ps[1:] {
-		minX = math.Min(minX, p[0])
-		maxX = math.Max(maxX, p[0])
-		minY = math.Min(minY, p[1])
-		maxY = math.Max(maxY, p[1])
-	}
-	xRes := (maxX - minX) / w
-	yRes := (maxY - minY) / h
+	xRes := (b.maxX - b.minX) / w
+	yRes := (b.maxY - b.minY) / h
 	res := math.Max(xRes, yRes)
 
 	return func(x, y float64) (float64, float64) {
-		return (x-minX)/res + padding.Left, (maxY-y)/res + padding.Top
+		return (x-b.minX)/res + padding.Left, (b.maxY-y)/res + padding.Top
 	}
 }
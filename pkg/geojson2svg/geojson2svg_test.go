@@ -2,13 +2,16 @@ package geojson2svg_test
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"path"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/fapian/geojson2svg/pkg/geojson2svg"
+	geojson "github.com/paulmach/go.geojson"
 )
 
 const wantEmptySVG = `<svg width="400.000000" height="400.000000"></svg>`
@@ -429,6 +432,14 @@ func TestSVGPaddingOption(t *testing.T) {
 			"[[0,0], [0,400], [400,400], [400,0]]",
 			geojson2svg.Padding{Top: 5, Right: 5, Bottom: 5, Left: 5},
 			`<svg width="200.000000" height="200.000000"><path d="M5.000000 195.000000,5.000000 5.000000,195.000000 5.000000,195.000000 195.000000"/></svg>`},
+		{"with uneven padding",
+			"[[0,0], [0,400], [400,400], [400,0]]",
+			geojson2svg.Padding{Top: 10, Right: 0, Bottom: 30, Left: 50},
+			`<svg width="200.000000" height="200.000000"><path d="M50.000000 160.000000,50.000000 10.000000,200.000000 10.000000,200.000000 160.000000"/></svg>`},
+		{"with padding and a single point",
+			"[[10,10]]",
+			geojson2svg.Padding{Top: 10, Right: 0, Bottom: 30, Left: 50},
+			`<svg width="200.000000" height="200.000000"><path d="M125.000000 90.000000"/></svg>`},
 	}
 
 	for _, tc := range tcs {
@@ -447,6 +458,46 @@ func TestSVGPaddingOption(t *testing.T) {
 	}
 }
 
+func TestSVGViewBoxOptions(t *testing.T) {
+	tcs := []struct {
+		name     string
+		opts     []geojson2svg.Option
+		expected string
+	}{
+		{"with viewbox",
+			[]geojson2svg.Option{geojson2svg.WithViewBox()},
+			`<svg width="400.000000" height="200.000000" viewBox="0 0 400.000000 200.000000">`},
+		{"with preserve aspect ratio",
+			[]geojson2svg.Option{geojson2svg.WithPreserveAspectRatio("xMinYMin slice")},
+			`<svg width="400.000000" height="200.000000" viewBox="0 0 400.000000 200.000000" preserveAspectRatio="xMinYMin slice">`},
+		{"with dimensions",
+			[]geojson2svg.Option{geojson2svg.WithDimensions("50%", "10em"), geojson2svg.WithAttribute("id", "map")},
+			`<svg width="50%" height="10em" id="map">`},
+		{"without dimensions",
+			[]geojson2svg.Option{geojson2svg.WithDimensions("", ""), geojson2svg.WithViewBox()},
+			`<svg viewBox="0 0 400.000000 200.000000">`},
+		{"responsive",
+			[]geojson2svg.Option{geojson2svg.Responsive()},
+			`<svg width="100%" viewBox="0 0 400.000000 200.000000">`},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			svg := geojson2svg.New()
+			err := svg.AddGeometry(`{"type": "LineString", "coordinates": [[0,0], [400,400]]}`)
+			if err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			// the drawing fits into the viewbox
+			expected := tc.expected + `<path d="M0.000000 200.000000,200.000000 0.000000"/></svg>`
+			got := svg.Draw(400, 200, tc.opts...)
+			if got != expected {
+				tt.Errorf("expected %s, got %s", expected, got)
+			}
+		})
+	}
+}
+
 func TestFeatureProperties(t *testing.T) {
 	tcs := []struct {
 		name      string
@@ -552,6 +603,142 @@ func TestFeatureProperties(t *testing.T) {
 	}
 }
 
+func TestAddObjects(t *testing.T) {
+	want := oneLine(`
+		<svg width="400.000000" height="400.000000">
+			<path d="M0.000000 291.638796,400.000000 0.000000"/>
+			<circle cx="1.337793" cy="298.327759" r="1" class="point"/>
+			<circle cx="400.000000" cy="0.000000" r="1" class="collection"/>
+		</svg>
+	`)
+
+	svg := geojson2svg.New()
+	svg.AddGeometryObject(geojson.NewLineStringGeometry([][]float64{{10.4, 20.5}, {40.3, 42.3}}))
+	f := geojson.NewPointFeature([]float64{10.5, 20})
+	f.SetProperty("class", "point")
+	svg.AddFeatureObject(f)
+	fc := geojson.NewFeatureCollection()
+	f = geojson.NewPointFeature([]float64{40.3, 42.3})
+	f.SetProperty("class", "collection")
+	fc.AddFeature(f)
+	fc.AddFeature(geojson.NewFeature(nil))
+	svg.AddFeatureCollectionObject(fc)
+
+	svg.AddGeometryObject(nil)
+	svg.AddFeatureObject(nil)
+	svg.AddFeatureCollectionObject(nil)
+
+	got := svg.Draw(400, 400)
+	if got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestAddFromReader(t *testing.T) {
+	tcs := []struct {
+		name     string
+		geojson  string
+		expected string
+	}{
+		{"geometry",
+			`{"type": "LineString", "coordinates": [[10.4,20.5], [40.3,42.3]]}`,
+			`<svg width="400.000000" height="400.000000"><path d="M0.000000 291.638796,400.000000 0.000000"/></svg>`},
+		{"geometry collection",
+			`{"type": "GeometryCollection", "geometries": [{"type": "LineString", "coordinates": [[10.4,20.5], [40.3,42.3]]}]}`,
+			`<svg width="400.000000" height="400.000000"><path d="M0.000000 291.638796,400.000000 0.000000"/></svg>`},
+		{"feature",
+			`{"type": "Feature", "properties": {"class": "a"}, "geometry": {"type": "LineString", "coordinates": [[10.4,20.5], [40.3,42.3]]}}`,
+			`<svg width="400.000000" height="400.000000"><path d="M0.000000 291.638796,400.000000 0.000000" class="a"/></svg>`},
+		{"feature without geometry",
+			`{"type": "Feature", "properties": {"class": "a"}, "geometry": null}`,
+			`<svg width="400.000000" height="400.000000"></svg>`},
+		{"feature collection",
+			`{"type": "FeatureCollection", "features": [{"type": "Feature", "properties": {"class": "a"}, "geometry": {"type": "LineString", "coordinates": [[10.4,20.5], [40.3,42.3]]}}]}`,
+			`<svg width="400.000000" height="400.000000"><path d="M0.000000 291.638796,400.000000 0.000000" class="a"/></svg>`},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			svg := geojson2svg.New()
+			if err := svg.AddFromReader(strings.NewReader(tc.geojson)); err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			if got := svg.Draw(400, 400); got != tc.expected {
+				tt.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestAddFromReaderErrors(t *testing.T) {
+	tcs := []struct {
+		name    string
+		geojson string
+	}{
+		{"no json", `"type": "Point"`},
+		{"unknown type", `{"type": "Circle", "coordinates": [1,2]}`},
+		{"missing type", `{"coordinates": [1,2]}`},
+		{"invalid geometry", `{"type": "Point", "coordinates": "1,2"}`},
+		{"invalid feature", `{"type": "Feature", "geometry": {"type": "Point", "coordinates": "1,2"}}`},
+		{"invalid feature collection", `{"type": "FeatureCollection", "features": {}}`},
+		{"empty position", `{"type": "Point", "coordinates": []}`},
+		{"short positions", `{"type": "LineString", "coordinates": [[1], [2]]}`},
+		{"short position in a collection", `{"type": "GeometryCollection", "geometries": [{"type": "Polygon", "coordinates": [[[0,0], [1], [1,1], [0,0]]]}]}`},
+		{"short position of a feature", `{"type": "Feature", "geometry": {"type": "MultiPoint", "coordinates": [[1,2], [3]]}}`},
+		{"short position in a feature collection", `{"type": "FeatureCollection", "features": [{"type": "Feature", "geometry": {"type": "Point", "coordinates": [1]}}]}`},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			svg := geojson2svg.New()
+			if err := svg.AddFromReader(strings.NewReader(tc.geojson)); err == nil {
+				tt.Errorf("expected an error")
+			}
+			if got := svg.Draw(400, 400); got != wantEmptySVG {
+				tt.Errorf("expected %s, got %s", wantEmptySVG, got)
+			}
+		})
+	}
+}
+
+type failingWriter struct{ n int }
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.n < len(p) {
+		return 0, errors.New("write failed")
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestDrawTo(t *testing.T) {
+	svg := geojson2svg.New()
+	err := svg.AddGeometry(`{"type": "LineString", "coordinates": [[10.4,20.5], [40.3,42.3]]}`)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	t.Run("writes the same svg as draw", func(tt *testing.T) {
+		want := svg.Draw(400, 400, geojson2svg.WithAttribute("id", "the_id"))
+		got := bytes.NewBufferString("")
+		if err := svg.DrawTo(got, 400, 400, geojson2svg.WithAttribute("id", "the_id")); err != nil {
+			tt.Fatalf("unexpected error %v", err)
+		}
+		if got.String() != want {
+			tt.Errorf("expected %s, got %s", want, got)
+		}
+	})
+
+	t.Run("returns write errors", func(tt *testing.T) {
+		for _, n := range []int{0, 10, 60} {
+			err := svg.DrawTo(&failingWriter{n: n}, 400, 400)
+			if err == nil || err.Error() != "write failed" {
+				tt.Errorf("expected write failed, got %v", err)
+			}
+		}
+	})
+}
+
 func TestExample(t *testing.T) {
 	exampleFile := path.Join("..", "..", "test", "example.json")
 	geojson, err := ioutil.ReadFile(exampleFile)
@@ -584,3 +771,93 @@ func TestExample(t *testing.T) {
 		t.Errorf("expected %s, got %s", string(want), got)
 	}
 }
+
+func TestIDPrefix(t *testing.T) {
+	tcs := []struct {
+		name   string
+		opts   []geojson2svg.Option
+		prefix string
+	}{
+		{"default", nil, ""},
+		{"root id", []geojson2svg.Option{geojson2svg.WithAttribute("id", "map")}, "map-"},
+		{"prefix", []geojson2svg.Option{geojson2svg.WithAttribute("id", "map"), geojson2svg.WithIDPrefix("a-")}, "a-"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			svg := geojson2svg.New()
+			err := svg.AddFeatureCollection(`{"type": "FeatureCollection", "features": [
+				{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[0,0], [10,10]]}, "properties": {"v": 1}},
+				{"type": "Feature", "geometry": {"type": "Point", "coordinates": [5,5]}, "properties": {"v": 2}}
+			]}`)
+			if err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			opts := append([]geojson2svg.Option{
+				geojson2svg.WithBounds(0, 0, 10, 10),
+				geojson2svg.WithTitle("t"),
+				geojson2svg.WithDescription("d"),
+				geojson2svg.WithStyle(geojson2svg.Style{Property: "v", Colors: []string{"red", "blue"}}),
+				geojson2svg.WithLegend(geojson2svg.Legend{}),
+				geojson2svg.WithLabels("v", geojson2svg.LabelsAlongLines()),
+				geojson2svg.WithPoints(geojson2svg.PointStyle{Symbol: `<circle r="1"/>`}),
+			}, tc.opts...)
+			got := svg.Draw(100, 100, opts...)
+
+			want := []string{
+				`aria-labelledby="` + tc.prefix + `svg-title ` + tc.prefix + `svg-desc"`,
+				`<title id="` + tc.prefix + `svg-title">`,
+				`<desc id="` + tc.prefix + `svg-desc">`,
+				`<symbol id="` + tc.prefix + `point-symbol"`,
+				`<use href="#` + tc.prefix + `point-symbol"`,
+				`<clipPath id="` + tc.prefix + `bounds">`,
+				`clip-path="url(#` + tc.prefix + `bounds)"`,
+				`<path id="` + tc.prefix + `label-path-0"`,
+				`<textPath href="#` + tc.prefix + `label-path-0"`,
+				`<linearGradient id="` + tc.prefix + `legend-gradient">`,
+				`fill="url(#` + tc.prefix + `legend-gradient)"`,
+			}
+			for _, w := range want {
+				if !strings.Contains(got, w) {
+					tt.Errorf("expected %s to contain %s", got, w)
+				}
+			}
+		})
+	}
+}
+
+func TestParsePadding(t *testing.T) {
+	tcs := []struct {
+		s        string
+		expected geojson2svg.Padding
+		err      bool
+	}{
+		{"1", geojson2svg.Padding{Top: 1, Right: 1, Bottom: 1, Left: 1}, false},
+		{"1, 2", geojson2svg.Padding{Top: 1, Right: 2, Bottom: 1, Left: 2}, false},
+		{"1,2,3,4", geojson2svg.Padding{Top: 1, Right: 2, Bottom: 3, Left: 4}, false},
+		{"1,2,3", geojson2svg.Padding{}, true},
+		{"a", geojson2svg.Padding{}, true},
+		{"NaN", geojson2svg.Padding{}, true},
+		{"1,Inf", geojson2svg.Padding{}, true},
+		{"-1", geojson2svg.Padding{}, true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.s, func(tt *testing.T) {
+			got, err := geojson2svg.ParsePadding(tc.s)
+			if (err != nil) != tc.err {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			if got != tc.expected {
+				tt.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseProperties(t *testing.T) {
+	want := []string{"a", "b c"}
+	if got := geojson2svg.ParseProperties(" a,, b c ,"); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
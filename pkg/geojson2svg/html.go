@@ -0,0 +1,140 @@
+package geojson2svg
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+)
+
+// HTML represents the options of a HTML page showing the svg.
+type HTML struct {
+	// Title is the title of the page.
+	Title string
+	// PanZoom enables panning by dragging and zooming with the mouse wheel.
+	// It requires a viewBox, which is enabled for the svg.
+	PanZoom bool
+	// Highlight enables highlighting the feature under the mouse. Features
+	// with data-* attributes (see WithDataAttributes) are highlighted as a
+	// whole and their attributes are shown in a tooltip.
+	Highlight bool
+}
+
+// DrawHTML renders the svg with the given options embedded in a standalone
+// HTML page to w. The page has no external dependencies, its scripts and
+// styles are inlined.
+func (svg *SVG) DrawHTML(w io.Writer, width, height float64, page HTML, opts ...Option) error {
+	if page.PanZoom {
+		opts = append(opts, WithViewBox())
+	}
+
+	bw := bufio.NewWriter(w)
+	ew := &errWriter{w: bw}
+	fmt.Fprintf(ew, `<!DOCTYPE html><html><head><meta charset="utf-8"><title>%s</title>`, html.EscapeString(page.Title))
+	fmt.Fprintf(ew, `<style>%s</style></head><body><div id="geojson2svg-map">`, htmlStyle)
+	if err := svg.DrawTo(ew, width, height, opts...); err != nil {
+		return err
+	}
+	fmt.Fprint(ew, `</div>`)
+	if page.Highlight {
+		fmt.Fprintf(ew, `<div id="geojson2svg-tooltip"></div><script>%s</script>`, htmlHighlight)
+	}
+	if page.PanZoom {
+		fmt.Fprintf(ew, `<script>%s</script>`, htmlPanZoom)
+	}
+	fmt.Fprint(ew, `</body></html>`)
+	if ew.err != nil {
+		return ew.err
+	}
+	return bw.Flush()
+}
+
+const htmlStyle = `body{margin:0;font-family:sans-serif}` +
+	`#geojson2svg-map svg{display:block;cursor:default}` +
+	`#geojson2svg-map .geojson2svg-highlight,#geojson2svg-map .geojson2svg-highlight *{stroke:#f60;stroke-width:2px;vector-effect:non-scaling-stroke}` +
+	`#geojson2svg-tooltip{position:fixed;display:none;pointer-events:none;padding:4px 8px;background:#fff;border:1px solid #999;font-size:12px;white-space:pre}`
+
+// htmlHighlight highlights the feature under the mouse, a feature is the
+// nearest element with data attributes or the shape itself.
+const htmlHighlight = `(function () {
+	var svg = document.querySelector("#geojson2svg-map svg");
+	var tooltip = document.getElementById("geojson2svg-tooltip");
+	var current = null;
+	function data(el) {
+		var res = [];
+		for (var i = 0; i < el.attributes.length; i++) {
+			var a = el.attributes[i];
+			if (a.name.indexOf("data-") === 0) {
+				res.push(a.name.substring(5) + ": " + a.value);
+			}
+		}
+		return res;
+	}
+	function feature(el) {
+		for (var x = el; x && x !== svg; x = x.parentNode) {
+			if (data(x).length > 0) {
+				return x;
+			}
+		}
+		return /^(path|circle|rect|use)$/.test(el.nodeName) ? el : null;
+	}
+	svg.addEventListener("mousemove", function (e) {
+		var f = feature(e.target);
+		if (f !== current) {
+			if (current) {
+				current.classList.remove("geojson2svg-highlight");
+			}
+			if (f) {
+				f.classList.add("geojson2svg-highlight");
+			}
+			current = f;
+		}
+		var lines = f ? data(f) : [];
+		tooltip.style.display = lines.length > 0 ? "block" : "none";
+		tooltip.textContent = lines.join("\n");
+		tooltip.style.left = (e.clientX + 12) + "px";
+		tooltip.style.top = (e.clientY + 12) + "px";
+	});
+	svg.addEventListener("mouseleave", function () {
+		if (current) {
+			current.classList.remove("geojson2svg-highlight");
+			current = null;
+		}
+		tooltip.style.display = "none";
+	});
+})();`
+
+// htmlPanZoom pans and zooms the svg by changing its viewBox.
+const htmlPanZoom = `(function () {
+	var svg = document.querySelector("#geojson2svg-map svg");
+	var vb = svg.viewBox.baseVal;
+	var drag = null;
+	function point(e) {
+		var r = svg.getBoundingClientRect();
+		return {x: vb.x + (e.clientX - r.left) / r.width * vb.width, y: vb.y + (e.clientY - r.top) / r.height * vb.height};
+	}
+	svg.addEventListener("wheel", function (e) {
+		e.preventDefault();
+		var p = point(e);
+		var s = e.deltaY > 0 ? 1.2 : 1 / 1.2;
+		vb.x = p.x - (p.x - vb.x) * s;
+		vb.y = p.y - (p.y - vb.y) * s;
+		vb.width *= s;
+		vb.height *= s;
+	});
+	svg.addEventListener("mousedown", function (e) {
+		drag = point(e);
+		svg.style.cursor = "move";
+	});
+	window.addEventListener("mousemove", function (e) {
+		if (drag) {
+			var p = point(e);
+			vb.x -= p.x - drag.x;
+			vb.y -= p.y - drag.y;
+		}
+	});
+	window.addEventListener("mouseup", function () {
+		drag = null;
+		svg.style.cursor = "";
+	});
+})();`
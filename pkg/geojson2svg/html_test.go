@@ -0,0 +1,81 @@
+package geojson2svg_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fapian/geojson2svg/pkg/geojson2svg"
+)
+
+func TestDrawHTML(t *testing.T) {
+	tcs := []struct {
+		name     string
+		page     geojson2svg.HTML
+		contains []string
+		excludes []string
+	}{
+		{"plain",
+			geojson2svg.HTML{Title: "a <map>"},
+			[]string{"<!DOCTYPE html>", "<title>a &lt;map&gt;</title>", `<svg width="200.000000" height="100.000000">`, "</svg></div></body></html>"},
+			[]string{"<script>", "viewBox"}},
+		{"pan and zoom",
+			geojson2svg.HTML{PanZoom: true},
+			[]string{`viewBox="0 0 200.000000 100.000000"`, "viewBox.baseVal"},
+			[]string{"geojson2svg-tooltip\"></div>"}},
+		{"highlight",
+			geojson2svg.HTML{Highlight: true},
+			[]string{`<div id="geojson2svg-tooltip"></div>`, "classList.add(\"geojson2svg-highlight\")"},
+			[]string{"viewBox"}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			svg := geojson2svg.New()
+			if err := svg.AddGeometry(`{"type": "LineString", "coordinates": [[0,0], [2,1]]}`); err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			var buf bytes.Buffer
+			if err := svg.DrawHTML(&buf, 200, 100, tc.page); err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			got := buf.String()
+			for _, s := range tc.contains {
+				if !strings.Contains(got, s) {
+					tt.Errorf("expected %s to contain %s", got, s)
+				}
+			}
+			for _, s := range tc.excludes {
+				if strings.Contains(got, s) {
+					tt.Errorf("expected %s not to contain %s", got, s)
+				}
+			}
+		})
+	}
+}
+
+func TestDrawHTMLEmbedsDrawing(t *testing.T) {
+	svg := geojson2svg.New()
+	if err := svg.AddFeature(`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [1,1]}, "properties": {"name": "x"}}`); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	opts := []geojson2svg.Option{geojson2svg.WithDataAttributes(nil)}
+	want := svg.Draw(100, 100, opts...)
+
+	var buf bytes.Buffer
+	if err := svg.DrawHTML(&buf, 100, 100, geojson2svg.HTML{Highlight: true}, opts...); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("expected %s to contain %s", buf.String(), want)
+	}
+}
+
+func TestDrawHTMLError(t *testing.T) {
+	svg := geojson2svg.New()
+	var buf bytes.Buffer
+	err := svg.DrawHTML(&buf, 100, 100, geojson2svg.HTML{}, geojson2svg.WithFeatureTitle("{{"))
+	if err == nil {
+		t.Errorf("expected an error")
+	}
+}
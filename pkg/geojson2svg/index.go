@@ -0,0 +1,185 @@
+package geojson2svg
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	geojson "github.com/paulmach/go.geojson"
+)
+
+// nodeCapacity is the maximum number of children of a node of the index.
+const nodeCapacity = 16
+
+// featureIndex is a spatial index over the geometries and features of a
+// svg, which is used to draw only the features intersecting the extent.
+// It is a R-tree bulk loaded with the sort-tile-recursive algorithm, see
+// Leutenegger et al. "STR: A Simple and Efficient Algorithm for R-Tree
+// Packing". The tree is built lazily and rebuilt when data was added to
+// the svg. Copies of a svg share the index.
+type featureIndex struct {
+	mu      sync.Mutex
+	built   bool
+	version int
+	root    *indexNode
+}
+
+// indexNode is a node of the index. Leaves hold an entry, inner nodes
+// their children.
+type indexNode struct {
+	b        box
+	children []*indexNode
+	entry    *indexEntry
+}
+
+// indexEntry is a geometry or a feature with its position in the order
+// they were added.
+type indexEntry struct {
+	order    int
+	geometry *geojson.Geometry
+	feature  *geojson.Feature
+}
+
+// tree returns the root of the index of the svg, rebuilding it if data was
+// added since it was built. The root is nil if the svg has no data. The
+// tree is never modified once it is built.
+func (svg *SVG) tree() *indexNode {
+	ix := svg.index
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if !ix.built || ix.version != svg.version {
+		ix.root = packTree(svg.indexLeaves())
+		ix.version = svg.version
+		ix.built = true
+	}
+	return ix.root
+}
+
+// search returns the geometries and the features of the svg whose bounding
+// boxes intersect q in the order they were added.
+func (svg *SVG) search(q box) ([]*geojson.Geometry, []*geojson.Feature) {
+	es := []*indexEntry{}
+	if root := svg.tree(); root != nil {
+		es = root.search(q, es)
+	}
+	sort.Slice(es, func(i, j int) bool { return es[i].order < es[j].order })
+
+	gs := []*geojson.Geometry{}
+	fs := []*geojson.Feature{}
+	for _, e := range es {
+		if e.feature != nil {
+			fs = append(fs, e.feature)
+		} else {
+			gs = append(gs, e.geometry)
+		}
+	}
+	return gs, fs
+}
+
+// dataBounds returns the bounding box of the geometries of the svg and its
+// layers. It returns false if there are none.
+func (svg *SVG) dataBounds() (box, bool) {
+	var b box
+	ok := false
+	if root := svg.tree(); root != nil {
+		b, ok = root.b, true
+	}
+	for _, l := range svg.layers {
+		lb, lok := l.dataBounds()
+		switch {
+		case lok && ok:
+			b = b.union(lb)
+		case lok:
+			b, ok = lb, true
+		}
+	}
+	return b, ok
+}
+
+func (n *indexNode) search(q box, es []*indexEntry) []*indexEntry {
+	if !n.b.overlaps(q) {
+		return es
+	}
+	if n.entry != nil {
+		return append(es, n.entry)
+	}
+	for _, c := range n.children {
+		es = c.search(q, es)
+	}
+	return es
+}
+
+// indexLeaves returns the leaves of the geometries and the features of the
+// svg without its layers. Empty geometries are left out.
+func (svg *SVG) indexLeaves() []*indexNode {
+	ns := []*indexNode{}
+	add := func(g *geojson.Geometry, f *geojson.Feature) {
+		if ps := collect(g); len(ps) > 0 {
+			ns = append(ns, &indexNode{b: bounds(ps), entry: &indexEntry{order: len(ns), geometry: g, feature: f}})
+		}
+	}
+	for _, g := range svg.geometries {
+		add(g, nil)
+	}
+	for _, f := range svg.ownFeatures() {
+		add(f.Geometry, f)
+	}
+	return ns
+}
+
+// packTree packs the nodes level by level into a tree and returns its root.
+func packTree(ns []*indexNode) *indexNode {
+	if len(ns) == 0 {
+		return nil
+	}
+	for len(ns) > nodeCapacity {
+		ns = packLevel(ns)
+	}
+	return newIndexNode(ns)
+}
+
+// packLevel groups the nodes into parents of nodeCapacity children: the
+// nodes are sorted by x into vertical slices, which are sorted by y and cut
+// into runs of nodeCapacity nodes.
+func packLevel(ns []*indexNode) []*indexNode {
+	parents := (len(ns) + nodeCapacity - 1) / nodeCapacity
+	perSlice := int(math.Ceil(math.Sqrt(float64(parents)))) * nodeCapacity
+
+	sort.Slice(ns, func(i, j int) bool { return ns[i].b.minX+ns[i].b.maxX < ns[j].b.minX+ns[j].b.maxX })
+	res := make([]*indexNode, 0, parents)
+	for i := 0; i < len(ns); i += perSlice {
+		slice := ns[i:minInt(i+perSlice, len(ns))]
+		sort.Slice(slice, func(i, j int) bool { return slice[i].b.minY+slice[i].b.maxY < slice[j].b.minY+slice[j].b.maxY })
+		for j := 0; j < len(slice); j += nodeCapacity {
+			res = append(res, newIndexNode(slice[j:minInt(j+nodeCapacity, len(slice))]))
+		}
+	}
+	return res
+}
+
+// newIndexNode returns the parent of the nodes.
+func newIndexNode(children []*indexNode) *indexNode {
+	b := children[0].b
+	for _, c := range children[1:] {
+		b = b.union(c.b)
+	}
+	return &indexNode{b: b, children: children}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// overlaps returns whether the boxes intersect or touch, unlike intersects
+// it is true for the empty boxes of single points.
+func (b box) overlaps(o box) bool {
+	return b.minX <= o.maxX && b.maxX >= o.minX && b.minY <= o.maxY && b.maxY >= o.minY
+}
+
+// union returns the bounding box of both boxes.
+func (b box) union(o box) box {
+	return box{math.Min(b.minX, o.minX), math.Min(b.minY, o.minY), math.Max(b.maxX, o.maxX), math.Max(b.maxY, o.maxY)}
+}
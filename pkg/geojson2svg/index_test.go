@@ -0,0 +1,145 @@
+package geojson2svg_test
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fapian/geojson2svg/pkg/geojson2svg"
+)
+
+// gridSVG returns a svg with a point feature at every integer coordinate
+// from 0,0 to n-1,n-1, the class of a point is its position.
+func gridSVG(t *testing.T, n int) *geojson2svg.SVG {
+	fs := []string{}
+	for x := 0; x < n; x++ {
+		for y := 0; y < n; y++ {
+			fs = append(fs, fmt.Sprintf(`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [%d,%d]}, "properties": {"class": "p%d-%d"}}`, x, y, x, y))
+		}
+	}
+	svg := geojson2svg.New()
+	if err := svg.AddFeatureCollection(`{"type": "FeatureCollection", "features": [` + strings.Join(fs, ",") + `]}`); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return svg
+}
+
+func TestIndexQueriesExtent(t *testing.T) {
+	svg := gridSVG(t, 100)
+	// 10 units on 100 pixels, the clip margin of 8 pixels adds 0.8 units
+	got := svg.Draw(100, 100, geojson2svg.WithBounds(10, 10, 20, 20))
+
+	classes := regexp.MustCompile(`class="(p[0-9-]+)"`).FindAllStringSubmatch(got, -1)
+	want := []string{}
+	for x := 10; x <= 20; x++ {
+		for y := 10; y <= 20; y++ {
+			want = append(want, fmt.Sprintf("p%d-%d", x, y))
+		}
+	}
+	if len(classes) != len(want) {
+		t.Fatalf("expected %d points, got %d", len(want), len(classes))
+	}
+	for i, c := range classes {
+		if c[1] != want[i] {
+			t.Fatalf("expected point %d to be %s, got %s", i, want[i], c[1])
+		}
+	}
+}
+
+// recordingClassifier puts every value in the same class and records the
+// values it is asked to classify.
+type recordingClassifier struct {
+	classified *[]string
+}
+
+func (c recordingClassifier) Classify(values []interface{}, n int) geojson2svg.Classes {
+	return c
+}
+
+func (c recordingClassifier) Len() int { return 1 }
+
+func (c recordingClassifier) Class(v interface{}) int {
+	*c.classified = append(*c.classified, fmt.Sprint(v))
+	return 0
+}
+
+func (c recordingClassifier) Label(i int) string { return "" }
+
+func TestIndexSkipsFeaturesOutsideExtent(t *testing.T) {
+	svg := gridSVG(t, 100)
+	classified := []string{}
+	svg.Draw(100, 100,
+		geojson2svg.WithBounds(10, 10, 20, 20),
+		geojson2svg.WithStyle(geojson2svg.Style{
+			Property:   "class",
+			Classifier: recordingClassifier{&classified},
+			Colors:     []string{"red"},
+		}))
+
+	// without the index every feature would be styled before it is clipped
+	if len(classified) != 11*11 {
+		t.Fatalf("expected %d styled features, got %d", 11*11, len(classified))
+	}
+	for _, c := range classified {
+		var x, y int
+		if _, err := fmt.Sscanf(c, "p%d-%d", &x, &y); err != nil || x < 10 || x > 20 || y < 10 || y > 20 {
+			t.Errorf("expected only features inside the extent to be styled, got %s", c)
+		}
+	}
+}
+
+func TestIndexIsRebuilt(t *testing.T) {
+	svg := geojson2svg.New()
+	if err := svg.AddGeometry(`{"type": "LineString", "coordinates": [[0,0], [10,10]]}`); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if want, got := `<path d="M0.000000 100.000000,100.000000 0.000000"/>`, svg.Draw(100, 100); !strings.Contains(got, want) {
+		t.Errorf("expected %s to contain %s", got, want)
+	}
+
+	if err := svg.AddFeature(`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [20,20]}}`); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if want, got := `<path d="M0.000000 100.000000,50.000000 50.000000"/>`, svg.Draw(100, 100); !strings.Contains(got, want) {
+		t.Errorf("expected %s to contain %s", got, want)
+	}
+
+	if err := svg.Layer("l").AddFeatureCollection(`{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "geometry": {"type": "Point", "coordinates": [-20,-20]}}
+	]}`); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if want, got := `<path d="M50.000000 50.000000,75.000000 25.000000"/>`, svg.Draw(100, 100); !strings.Contains(got, want) {
+		t.Errorf("expected %s to contain %s", got, want)
+	}
+}
+
+func TestIndexConcurrentTiles(t *testing.T) {
+	h := &geojson2svg.TileHandler{SVG: gridSVG(t, 50)}
+	paths := []string{"/1/0/0.svg", "/1/1/0.svg", "/1/0/1.svg", "/1/1/1.svg"}
+	serve := func(p string) string {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", p, nil))
+		return rec.Body.String()
+	}
+
+	got := make([]string, 4*len(paths))
+	var wg sync.WaitGroup
+	for i := range got {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			got[i] = serve(paths[i%len(paths)])
+		}(i)
+	}
+	wg.Wait()
+
+	for i, g := range got {
+		if want := serve(paths[i%len(paths)]); g != want {
+			t.Errorf("expected %s, got %s", want, g)
+		}
+	}
+}
@@ -0,0 +1,392 @@
+package geojson2svg
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+
+	geojson "github.com/paulmach/go.geojson"
+)
+
+// A LabelOption represents a single label option.
+type LabelOption func(*labels)
+
+type labels struct {
+	property        string
+	fontSize        float64
+	alongLines      bool
+	attributes      map[string]string
+	avoidCollisions bool
+	priority        string
+}
+
+// WithLabels configures the SVG to label every feature with the value of the
+// given feature property. The labels are anchored at the point itself for
+// points, at the middle of linestrings and at the visual center (the pole
+// of inaccessibility) of polygons. Multi geometries are labeled once at
+// their largest part.
+func WithLabels(property string, opts ...LabelOption) Option {
+	return func(svg *SVG) {
+		l := &labels{
+			property:   property,
+			fontSize:   12,
+			attributes: make(map[string]string),
+		}
+		for _, o := range opts {
+			o(l)
+		}
+		svg.labels = l
+	}
+}
+
+// LabelFontSize sets the font size of the labels. Defaults to 12.
+func LabelFontSize(size float64) LabelOption {
+	return func(l *labels) {
+		l.fontSize = size
+	}
+}
+
+// LabelsAlongLines configures linestrings to be labeled with a text path
+// following the line instead of a horizontal text.
+func LabelsAlongLines() LabelOption {
+	return func(l *labels) {
+		l.alongLines = true
+	}
+}
+
+// LabelAttribute adds the key value pair as attribute to every label.
+func LabelAttribute(k, v string) LabelOption {
+	return func(l *labels) {
+		l.attributes[k] = v
+	}
+}
+
+// AvoidLabelCollisions configures the labels to be placed without
+// overlapping each other. Every label is tried at several positions around
+// its anchor, labels which collide at all positions or do not fit into the
+// svg are dropped.
+func AvoidLabelCollisions() LabelOption {
+	return func(l *labels) {
+		l.avoidCollisions = true
+	}
+}
+
+// LabelPriority configures the numeric feature property which decides which
+// labels win when labels collide. Labels with higher values are placed
+// first, labels without a value last.
+func LabelPriority(property string) LabelOption {
+	return func(l *labels) {
+		l.priority = property
+	}
+}
+
+type anchorKind int
+
+const (
+	pointAnchor anchorKind = iota
+	lineAnchor
+	areaAnchor
+)
+
+// labelAnchor is the position of a label in svg coordinates.
+type labelAnchor struct {
+	kind anchorKind
+	x, y float64
+	// line is the scaled linestring of a line anchor.
+	line [][]float64
+}
+
+// label is a label of a feature and its position.
+type label struct {
+	text     string
+	anchor   labelAnchor
+	priority float64
+	placement
+}
+
+func drawLabels(r renderer, sf scaleFunc, l *labels, fs []*geojson.Feature, width, height float64) {
+	ls := collectLabels(sf, l, fs)
+	if l.avoidCollisions {
+		ls = placeLabels(ls, l, width, height)
+	}
+
+	for _, lb := range ls {
+		if lb.anchor.kind == lineAnchor && l.alongLines {
+			r.textAlong(readable(lb.anchor.line), lb.text, l.fontSize, l.attributes)
+			continue
+		}
+		r.text(lb.x, lb.y, lb.text, l.fontSize, lb.textAnchor, l.attributes)
+	}
+}
+
+func collectLabels(sf scaleFunc, l *labels, fs []*geojson.Feature) []label {
+	ls := []label{}
+	for _, f := range fs {
+		text, ok := labelText(l, f)
+		if !ok {
+			continue
+		}
+		a, ok := anchor(sf, f.Geometry)
+		if !ok {
+			continue
+		}
+		priority := math.Inf(-1)
+		if v, ok := toFloat(f.Properties[l.priority]); ok && l.priority != "" {
+			priority = v
+		}
+		lb := label{text: text, anchor: a, priority: priority}
+		lb.placement = candidates(lb, l)[0]
+		ls = append(ls, lb)
+	}
+	return ls
+}
+
+func labelText(l *labels, f *geojson.Feature) (string, bool) {
+	v, ok := f.Properties[l.property]
+	if !ok || v == nil {
+		return "", false
+	}
+	text := fmt.Sprintf("%v", v)
+	return text, text != ""
+}
+
+// anchor returns the label anchor of the geometry.
+func anchor(sf scaleFunc, g *geojson.Geometry) (labelAnchor, bool) {
+	if g == nil {
+		return labelAnchor{}, false
+	}
+	switch {
+	case g.IsPoint():
+		x, y := sf(g.Point[0], g.Point[1])
+		return labelAnchor{kind: pointAnchor, x: x, y: y}, true
+	case g.IsMultiPoint():
+		if len(g.MultiPoint) == 0 {
+			return labelAnchor{}, false
+		}
+		x, y := sf(g.MultiPoint[0][0], g.MultiPoint[0][1])
+		return labelAnchor{kind: pointAnchor, x: x, y: y}, true
+	case g.IsLineString():
+		return lineStringAnchor(scaleLine(sf, g.LineString))
+	case g.IsMultiLineString():
+		var longest [][]float64
+		for _, ps := range g.MultiLineString {
+			if l := scaleLine(sf, ps); lineLength(l) > lineLength(longest) {
+				longest = l
+			}
+		}
+		return lineStringAnchor(longest)
+	case g.IsPolygon():
+		return polygonAnchor(scalePolygon(sf, g.Polygon))
+	case g.IsMultiPolygon():
+		var largest [][][]float64
+		for _, pps := range g.MultiPolygon {
+			if p := scalePolygon(sf, pps); len(p) > 0 && (largest == nil || math.Abs(ringArea(p[0])) > math.Abs(ringArea(largest[0]))) {
+				largest = p
+			}
+		}
+		return polygonAnchor(largest)
+	case g.IsCollection():
+		for _, x := range g.Geometries {
+			if a, ok := anchor(sf, x); ok {
+				return a, true
+			}
+		}
+	}
+	return labelAnchor{}, false
+}
+
+func lineStringAnchor(ps [][]float64) (labelAnchor, bool) {
+	if len(ps) == 0 {
+		return labelAnchor{}, false
+	}
+	half := lineLength(ps) / 2
+	for i := 1; i < len(ps); i++ {
+		d := math.Hypot(ps[i][0]-ps[i-1][0], ps[i][1]-ps[i-1][1])
+		if d > 0 && d >= half {
+			t := half / d
+			x := ps[i-1][0] + (ps[i][0]-ps[i-1][0])*t
+			y := ps[i-1][1] + (ps[i][1]-ps[i-1][1])*t
+			return labelAnchor{kind: lineAnchor, x: x, y: y, line: ps}, true
+		}
+		half -= d
+	}
+	return labelAnchor{kind: lineAnchor, x: ps[0][0], y: ps[0][1], line: ps}, true
+}
+
+func polygonAnchor(pps [][][]float64) (labelAnchor, bool) {
+	if len(pps) == 0 || len(pps[0]) == 0 {
+		return labelAnchor{}, false
+	}
+	x, y := polylabel(pps, 1)
+	return labelAnchor{kind: areaAnchor, x: x, y: y}, true
+}
+
+func scaleLine(sf scaleFunc, ps [][]float64) [][]float64 {
+	res := make([][]float64, len(ps))
+	for i, p := range ps {
+		x, y := sf(p[0], p[1])
+		res[i] = []float64{x, y}
+	}
+	return res
+}
+
+func scalePolygon(sf scaleFunc, pps [][][]float64) [][][]float64 {
+	res := make([][][]float64, len(pps))
+	for i, ps := range pps {
+		res[i] = scaleLine(sf, ps)
+	}
+	return res
+}
+
+func lineLength(ps [][]float64) float64 {
+	l := 0.0
+	for i := 1; i < len(ps); i++ {
+		l += math.Hypot(ps[i][0]-ps[i-1][0], ps[i][1]-ps[i-1][1])
+	}
+	return l
+}
+
+// readable reverses the line if it runs from right to left, so a text
+// following it is not upside down.
+func readable(ps [][]float64) [][]float64 {
+	if ps[0][0] <= ps[len(ps)-1][0] {
+		return ps
+	}
+	res := make([][]float64, len(ps))
+	for i, p := range ps {
+		res[len(ps)-1-i] = p
+	}
+	return res
+}
+
+// ringArea returns the signed area of the ring.
+func ringArea(ps [][]float64) float64 {
+	a := 0.0
+	for i, j := 0, len(ps)-1; i < len(ps); j, i = i, i+1 {
+		a += ps[j][0]*ps[i][1] - ps[i][0]*ps[j][1]
+	}
+	return a / 2
+}
+
+// polylabel returns the pole of inaccessibility of the polygon, the point
+// inside the polygon with the largest distance to its outline, with the
+// given precision. See https://github.com/mapbox/polylabel.
+func polylabel(pps [][][]float64, precision float64) (float64, float64) {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range pps[0] {
+		minX, minY = math.Min(minX, p[0]), math.Min(minY, p[1])
+		maxX, maxY = math.Max(maxX, p[0]), math.Max(maxY, p[1])
+	}
+
+	// a sliver would create an enormous grid, cells are at least as large
+	// as the precision
+	size := math.Max(precision, math.Min(maxX-minX, maxY-minY))
+	if maxX == minX && maxY == minY {
+		return minX, minY
+	}
+
+	best := centroidCell(pps)
+	if bbox := newCell((minX+maxX)/2, (minY+maxY)/2, 0, pps); bbox.d > best.d {
+		best = bbox
+	}
+
+	cells := &cellQueue{}
+	h := size / 2
+	for x := minX; x < maxX; x += size {
+		for y := minY; y < maxY; y += size {
+			heap.Push(cells, newCell(x+h, y+h, h, pps))
+		}
+	}
+
+	for cells.Len() > 0 {
+		c := heap.Pop(cells).(cell)
+		if c.d > best.d {
+			best = c
+		}
+		if c.max-best.d <= precision {
+			continue
+		}
+		h := c.h / 2
+		heap.Push(cells, newCell(c.x-h, c.y-h, h, pps))
+		heap.Push(cells, newCell(c.x+h, c.y-h, h, pps))
+		heap.Push(cells, newCell(c.x-h, c.y+h, h, pps))
+		heap.Push(cells, newCell(c.x+h, c.y+h, h, pps))
+	}
+	return best.x, best.y
+}
+
+// cell is a square with the center x, y and half the side length h.
+// d is the signed distance of the center to the polygon and max the
+// maximum distance to the polygon within the cell.
+type cell struct {
+	x, y, h, d, max float64
+}
+
+func newCell(x, y, h float64, pps [][][]float64) cell {
+	d := pointToPolygonDistance(x, y, pps)
+	return cell{x: x, y: y, h: h, d: d, max: d + h*math.Sqrt2}
+}
+
+func centroidCell(pps [][][]float64) cell {
+	ps := pps[0]
+	var x, y, area float64
+	for i, j := 0, len(ps)-1; i < len(ps); j, i = i, i+1 {
+		f := ps[i][0]*ps[j][1] - ps[j][0]*ps[i][1]
+		x += (ps[i][0] + ps[j][0]) * f
+		y += (ps[i][1] + ps[j][1]) * f
+		area += f * 3
+	}
+	if area == 0 {
+		return newCell(ps[0][0], ps[0][1], 0, pps)
+	}
+	return newCell(x/area, y/area, 0, pps)
+}
+
+// pointToPolygonDistance returns the distance of the point to the outline
+// of the polygon, negative if the point is outside.
+func pointToPolygonDistance(x, y float64, pps [][][]float64) float64 {
+	inside := false
+	min := math.Inf(1)
+	for _, ps := range pps {
+		for i, j := 0, len(ps)-1; i < len(ps); j, i = i, i+1 {
+			a, b := ps[i], ps[j]
+			if (a[1] > y) != (b[1] > y) && x < (b[0]-a[0])*(y-a[1])/(b[1]-a[1])+a[0] {
+				inside = !inside
+			}
+			min = math.Min(min, segmentDistance(x, y, a, b))
+		}
+	}
+	if !inside {
+		return -min
+	}
+	return min
+}
+
+func segmentDistance(x, y float64, a, b []float64) float64 {
+	px, py := a[0], a[1]
+	dx, dy := b[0]-px, b[1]-py
+	if dx != 0 || dy != 0 {
+		t := ((x-px)*dx + (y-py)*dy) / (dx*dx + dy*dy)
+		if t > 1 {
+			px, py = b[0], b[1]
+		} else if t > 0 {
+			px, py = px+dx*t, py+dy*t
+		}
+	}
+	return math.Hypot(x-px, y-py)
+}
+
+// cellQueue is a max heap of cells ordered by their maximum distance.
+type cellQueue []cell
+
+func (q cellQueue) Len() int            { return len(q) }
+func (q cellQueue) Less(i, j int) bool  { return q[i].max > q[j].max }
+func (q cellQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *cellQueue) Push(x interface{}) { *q = append(*q, x.(cell)) }
+func (q *cellQueue) Pop() interface{} {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
@@ -0,0 +1,131 @@
+package geojson2svg_test
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fapian/geojson2svg/pkg/geojson2svg"
+)
+
+func TestLabels(t *testing.T) {
+	tcs := []struct {
+		name     string
+		feature  string
+		opts     []geojson2svg.LabelOption
+		expected string
+	}{
+		{"point",
+			`{"type": "Feature", "properties": {"name": "A"}, "geometry": {"type": "Point", "coordinates": [10.5,20]}}`,
+			nil,
+			`<text x="206.000000" y="200.000000" font-size="12" text-anchor="start" dominant-baseline="middle">A</text>`},
+		{"linestring",
+			`{"type": "Feature", "properties": {"name": "Main Street"}, "geometry": {"type": "LineString", "coordinates": [[0,0], [100,300], [400,400]]}}`,
+			nil,
+			`<text x="100.000000" y="100.000000" font-size="12" text-anchor="middle" dominant-baseline="middle">Main Street</text>`},
+		{"linestring along the line",
+			`{"type": "Feature", "properties": {"name": "Main Street"}, "geometry": {"type": "LineString", "coordinates": [[400,400], [0,0]]}}`,
+			[]geojson2svg.LabelOption{geojson2svg.LabelsAlongLines()},
+			`<defs><path id="label-path-0" d="M0.000000 400.000000,400.000000 0.000000"/></defs>` +
+				`<text font-size="12"><textPath href="#label-path-0" xlink:href="#label-path-0" startOffset="50%" text-anchor="middle">Main Street</textPath></text>`},
+		{"multilinestring",
+			`{"type": "Feature", "properties": {"name": "B"}, "geometry": {"type": "MultiLineString", "coordinates": [[[0,0], [0,100]], [[0,200], [400,200]]]}}`,
+			nil,
+			`<text x="200.000000" y="0.000000" font-size="12" text-anchor="middle" dominant-baseline="middle">B</text>`},
+		{"polygon",
+			`{"type": "Feature", "properties": {"name": 42}, "geometry": {"type": "Polygon", "coordinates": [[[0,0], [0,400], [400,400], [400,0], [0,0]]]}}`,
+			[]geojson2svg.LabelOption{geojson2svg.LabelFontSize(20), geojson2svg.LabelAttribute("class", "label")},
+			`<text x="200.000000" y="200.000000" font-size="20" text-anchor="middle" dominant-baseline="middle" class="label">42</text>`},
+		{"multipolygon",
+			`{"type": "Feature", "properties": {"name": "C"}, "geometry": {"type": "MultiPolygon", "coordinates": [
+				[[[0,0], [0,100], [100,100], [100,0], [0,0]]],
+				[[[200,200], [200,400], [400,400], [400,200], [200,200]]]
+			]}}`,
+			nil,
+			`<text x="300.000000" y="100.000000" font-size="12" text-anchor="middle" dominant-baseline="middle">C</text>`},
+		{"escaped",
+			`{"type": "Feature", "properties": {"name": "<A & B>"}, "geometry": {"type": "Point", "coordinates": [10.5,20]}}`,
+			nil,
+			`<text x="206.000000" y="200.000000" font-size="12" text-anchor="start" dominant-baseline="middle">&lt;A &amp; B&gt;</text>`},
+		{"without the property",
+			`{"type": "Feature", "properties": {"other": "A"}, "geometry": {"type": "Point", "coordinates": [10.5,20]}}`,
+			nil,
+			``},
+	}
+
+	re := regexp.MustCompile(`(<defs>.*)?<text.*</text>`)
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			svg := geojson2svg.New()
+			if err := svg.AddFeature(tc.feature); err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			got := re.FindString(svg.Draw(400, 400, geojson2svg.WithLabels("name", tc.opts...)))
+			if got != tc.expected {
+				tt.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestLabelsOfConcavePolygons(t *testing.T) {
+	// a U shaped polygon, its centroid lies outside of it
+	svg := geojson2svg.New()
+	err := svg.AddFeature(`{"type": "Feature", "properties": {"name": "U"}, "geometry": {"type": "Polygon", "coordinates": [
+		[[0,0], [300,0], [300,300], [200,300], [200,100], [100,100], [100,300], [0,300], [0,0]]
+	]}}`)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	got := svg.Draw(300, 300, geojson2svg.WithLabels("name"))
+	m := regexp.MustCompile(`<text x="([^"]*)" y="([^"]*)"`).FindStringSubmatch(got)
+	if m == nil {
+		t.Fatalf("expected a label, got %s", got)
+	}
+	x, _ := strconv.ParseFloat(m[1], 64)
+	y, _ := strconv.ParseFloat(m[2], 64)
+	y = 300 - y
+	// the largest inscribed circles touch the inner corners of the U
+	inside := x > 0 && x < 300 && y > 0 && y < 300 && (y < 100 || x < 100 || x > 200)
+	if !inside || math.Min(x, 300-x) < 50 || y < 50 {
+		t.Errorf("expected the label at a pole of inaccessibility, got (%f, %f)", x, y)
+	}
+}
+
+func TestLabelsOfSlivers(t *testing.T) {
+	svg := geojson2svg.New()
+	err := svg.AddFeature(`{"type": "Feature", "properties": {"name": "S"}, "geometry": {"type": "Polygon", "coordinates": [[[0,0], [10,0], [10,0.000001], [0,0]]]}}`)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	done := make(chan string)
+	go func() { done <- svg.Draw(400, 400, geojson2svg.WithLabels("name")) }()
+	select {
+	case got := <-done:
+		if !strings.Contains(got, ">S</text>") {
+			t.Errorf("expected %s to contain the label", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("labeling the sliver did not finish")
+	}
+}
+
+func TestLabelsAreDrawnOnTop(t *testing.T) {
+	svg := geojson2svg.New()
+	for _, name := range []string{"a", "b"} {
+		err := svg.AddFeature(fmt.Sprintf(`{"type": "Feature", "properties": {"name": "%s"}, "geometry": {"type": "Point", "coordinates": [%d,0]}}`, name, len(name)))
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+	got := svg.Draw(400, 400, geojson2svg.WithLabels("name"))
+	if strings.LastIndex(got, "<circle") > strings.Index(got, "<text") {
+		t.Errorf("expected the labels after the features, got %s", got)
+	}
+}
@@ -0,0 +1,116 @@
+package geojson2svg
+
+import "sort"
+
+// Layer returns the layer of the svg with the given id, creating it if it
+// does not exist yet, and applies the options to it.
+// A layer is a SVG of its own which is drawn into a <g> element with the
+// given id. Its geometries, features and featurecollections are added with
+// the usual methods and its attributes, properties, style and points are
+// configured with the usual options. All other options, e.g. the padding
+// or the projection, are taken from the root SVG.
+func (svg *SVG) Layer(id string, opts ...Option) *SVG {
+	var l *SVG
+	for _, x := range svg.layers {
+		if x.id == id {
+			l = x
+			break
+		}
+	}
+	if l == nil {
+		l = New()
+		l.id = id
+		svg.layers = append(svg.layers, l)
+	}
+	for _, o := range opts {
+		o(l)
+	}
+	return l
+}
+
+// WithZIndex configures the z-order of a layer. Layers with a higher
+// z-index are drawn above layers with a lower one, layers with the same
+// z-index in the order they were created. Layers with a negative z-index
+// are drawn below the content of their parent, all others above it.
+func WithZIndex(z int) Option {
+	return func(svg *SVG) {
+		svg.zIndex = z
+	}
+}
+
+// sortedLayers returns the layers in the order they are drawn.
+func (svg *SVG) sortedLayers() []*SVG {
+	ls := append([]*SVG{}, svg.layers...)
+	sort.SliceStable(ls, func(i, j int) bool { return ls[i].zIndex < ls[j].zIndex })
+	return ls
+}
+
+// drawContent draws the geometries, features and featurecollections of the
+// svg and its layers. It stops at the first error of the renderer.
+func (svg *SVG) drawContent(d *drawer, st *styling) error {
+	layers := svg.sortedLayers()
+	for _, l := range layers {
+		if l.zIndex < 0 {
+			if err := l.drawLayer(*d); err != nil {
+				return err
+			}
+		}
+	}
+
+	geometries, features := svg.geometries, svg.ownFeatures()
+	if d.query != nil {
+		geometries, features = svg.search(*d.query)
+	}
+	for _, g := range geometries {
+		if err := d.r.err(); err != nil {
+			return err
+		}
+		d.radius = d.points.radius(nil)
+		d.process(g, svg.geometryAttributes(g))
+	}
+	for _, f := range features {
+		if err := d.r.err(); err != nil {
+			return err
+		}
+		title, desc, err := svg.featureMetadata(f)
+		if err != nil {
+			return err
+		}
+		d.radius = d.points.radius(f.Properties)
+		d.drawFeature(f.Geometry, svg.featureAttributes(st, f), svg.dataAttributes(f), title, desc)
+	}
+
+	for _, l := range layers {
+		if l.zIndex >= 0 {
+			if err := l.drawLayer(*d); err != nil {
+				return err
+			}
+		}
+	}
+	return d.r.err()
+}
+
+// drawLayer draws the layer into a group with its own styling.
+func (svg *SVG) drawLayer(d drawer) error {
+	if err := svg.templateErr(); err != nil {
+		return err
+	}
+	attrs := make(map[string]string, len(svg.attributes)+1)
+	for k, v := range svg.attributes {
+		attrs[k] = v
+	}
+	attrs["id"] = svg.id
+	d.r.group(attrs, "", "")
+
+	var st *styling
+	if svg.style != nil {
+		st = newStyling(*svg.style, svg.ownFeatures())
+	}
+	d.points = newPointStyling(svg.pointStyle, svg.ownFeatures(), d.prefix+svg.id+"-"+pointSymbolID)
+	d.r.defs(svg.css(), d.points)
+	if err := svg.drawContent(&d, st); err != nil {
+		return err
+	}
+	d.r.end()
+	return nil
+}
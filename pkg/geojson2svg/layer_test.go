@@ -0,0 +1,60 @@
+package geojson2svg_test
+
+import (
+	"testing"
+
+	"github.com/fapian/geojson2svg/pkg/geojson2svg"
+)
+
+func TestLayers(t *testing.T) {
+	svg := geojson2svg.New()
+	if err := svg.AddGeometry(`{"type": "Point", "coordinates": [0,0]}`); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	roads := svg.Layer("roads", geojson2svg.WithAttribute("stroke", "black"), geojson2svg.WithZIndex(1))
+	if err := roads.AddFeature(`{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[0,0], [100,100]]}, "properties": {"class": "road"}}`); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	base := svg.Layer("base", geojson2svg.WithZIndex(-1), geojson2svg.UseProperties([]string{"kind"}))
+	if err := base.AddFeature(`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [100,0]}, "properties": {"class": "x", "kind": "land"}}`); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if svg.Layer("roads") != roads {
+		t.Fatalf("expected the existing layer")
+	}
+
+	expected := `<svg width="100" height="100">` +
+		`<g id="base"><circle cx="100" cy="100" r="1" kind="land"/></g>` +
+		`<circle cx="0" cy="100" r="1"/>` +
+		`<g id="roads" stroke="black"><path d="M0 100,100 0" class="road"/></g>` +
+		`</svg>`
+	got := svg.Draw(100, 100, geojson2svg.WithPrecision(0))
+	if got != expected {
+		t.Errorf("expected %s\ngot %s", expected, got)
+	}
+}
+
+func TestLayerStyle(t *testing.T) {
+	svg := geojson2svg.New()
+	l := svg.Layer("states", geojson2svg.WithStyle(geojson2svg.Style{
+		Property:   "kind",
+		Classifier: geojson2svg.Categorical(),
+		Colors:     []string{"red", "blue"},
+	}))
+	err := l.AddFeatureCollection(`{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0,0]}, "properties": {"kind": "a"}},
+		{"type": "Feature", "geometry": {"type": "Point", "coordinates": [100,100]}, "properties": {"kind": "b"}}
+	]}`)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := `<svg width="100" height="100">` +
+		`<g id="states"><circle cx="0" cy="100" r="1" fill="red"/><circle cx="100" cy="0" r="1" fill="blue"/></g>` +
+		`</svg>`
+	got := svg.Draw(100, 100, geojson2svg.WithPrecision(0))
+	if got != expected {
+		t.Errorf("expected %s\ngot %s", expected, got)
+	}
+}
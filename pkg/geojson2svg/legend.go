@@ -0,0 +1,119 @@
+package geojson2svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"unicode/utf8"
+)
+
+// A Corner is a corner of the svg.
+type Corner int
+
+// The corners of the svg.
+const (
+	TopLeft Corner = iota
+	TopRight
+	BottomLeft
+	BottomRight
+)
+
+// Legend represents the legend of a styled SVG. The legend is drawn inside
+// the svg at the given corner, use the padding of the SVG to reserve space
+// for it.
+type Legend struct {
+	Corner Corner
+	Title  string
+}
+
+const (
+	legendMargin   = 10
+	legendFontSize = 12
+	legendSwatch   = 12
+	legendRow      = legendSwatch + 4
+	legendGradient = 120
+)
+
+// WithLegend configures the SVG to draw a legend for its style. Classified
+// styles are explained with a swatch per class, continuous styles with a
+// gradient bar. Without a style, or if no feature has a numeric value for
+// a continuous style, no legend is drawn.
+func WithLegend(l Legend) Option {
+	return func(svg *SVG) {
+		svg.legend = &l
+	}
+}
+
+// textWidth estimates the width of the text s in the given font size.
+func textWidth(s string, fontSize float64) float64 {
+	return float64(utf8.RuneCountInString(s)) * fontSize * 0.6
+}
+
+func escapeText(s string) string {
+	res := bytes.NewBufferString("")
+	// writing to a bytes.Buffer never fails
+	_ = xml.EscapeText(res, []byte(s))
+	return res.String()
+}
+
+// drawLegend draws the legend with the shapes and texts of the renderer r
+// into a canvas of the given size.
+func drawLegend(r renderer, l Legend, st *styling, width, height float64) {
+	items := []func(x0, y0 float64){}
+	y := 0.0
+	w0 := 0.0
+	if l.Title != "" {
+		items = append(items, func(x0, y0 float64) {
+			r.text(x0, y0+legendSwatch/2, l.Title, legendFontSize, "start", map[string]string{"font-weight": "bold"})
+		})
+		y += legendRow
+		w0 = textWidth(l.Title, legendFontSize)
+	}
+
+	if st.classes != nil {
+		for i := 0; i < st.classes.Len(); i++ {
+			label, c, dy := st.classes.Label(i), st.Colors[i%len(st.Colors)], y
+			items = append(items, func(x0, y0 float64) {
+				r.rect(x0, y0+dy, legendSwatch, legendSwatch, map[string]string{"fill": c})
+				r.text(x0+legendSwatch+6, y0+dy+legendSwatch/2, label, legendFontSize, "start", nil)
+			})
+			y += legendRow
+			w0 = math.Max(w0, legendSwatch+6+textWidth(label, legendFontSize))
+		}
+		y -= legendRow - legendSwatch
+	} else {
+		dy := y
+		items = append(items, func(x0, y0 float64) {
+			r.gradient(x0, y0+dy, legendGradient, legendSwatch, st.Colors)
+			ty := y0 + dy + legendRow + legendSwatch/2
+			r.text(x0, ty, fmt.Sprintf("%g", st.min), legendFontSize, "start", nil)
+			r.text(x0+legendGradient, ty, fmt.Sprintf("%g", st.max), legendFontSize, "end", nil)
+		})
+		y += legendRow + legendSwatch
+		w0 = math.Max(w0, legendGradient)
+	}
+
+	x0, y0 := float64(legendMargin), float64(legendMargin)
+	if l.Corner == TopRight || l.Corner == BottomRight {
+		x0 = width - legendMargin - w0
+	}
+	if l.Corner == BottomLeft || l.Corner == BottomRight {
+		y0 = height - legendMargin - y
+	}
+	r.group(map[string]string{"class": "legend"}, "", "")
+	for _, item := range items {
+		item(x0, y0)
+	}
+	r.end()
+}
+
+// drawGradientSteps approximates the horizontal gradient of the colors in
+// the rectangle by a rectangle per step.
+func drawGradientSteps(r renderer, x, y, width, height float64, colors []string) {
+	const steps = 32
+	for i := 0; i < steps; i++ {
+		c := interpolateColor(colors, (float64(i)+0.5)/steps)
+		r.rect(x+float64(i)*width/steps, y, width/steps, height, map[string]string{"fill": c})
+	}
+}
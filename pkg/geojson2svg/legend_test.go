@@ -0,0 +1,85 @@
+package geojson2svg_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/fapian/geojson2svg/pkg/geojson2svg"
+)
+
+func TestLegend(t *testing.T) {
+	tcs := []struct {
+		name     string
+		values   []interface{}
+		style    geojson2svg.Style
+		legend   geojson2svg.Legend
+		expected string
+	}{
+		{"classes with title",
+			[]interface{}{1, 2, 3, 10, 11, 12, 50},
+			geojson2svg.Style{Property: "v", Classifier: geojson2svg.NaturalBreaks(), Colors: []string{"a", "b", "c"}},
+			geojson2svg.Legend{Corner: geojson2svg.TopLeft, Title: "Population"},
+			`<g class="legend">` +
+				`<text x="10.000000" y="16.000000" font-size="12" text-anchor="start" dominant-baseline="middle" font-weight="bold">Population</text>` +
+				`<rect x="10.000000" y="26.000000" width="12" height="12" fill="a"/>` +
+				`<text x="28.000000" y="32.000000" font-size="12" text-anchor="start" dominant-baseline="middle">1 – 3</text>` +
+				`<rect x="10.000000" y="42.000000" width="12" height="12" fill="b"/>` +
+				`<text x="28.000000" y="48.000000" font-size="12" text-anchor="start" dominant-baseline="middle">3 – 12</text>` +
+				`<rect x="10.000000" y="58.000000" width="12" height="12" fill="c"/>` +
+				`<text x="28.000000" y="64.000000" font-size="12" text-anchor="start" dominant-baseline="middle">12 – 50</text>` +
+				`</g>`},
+		{"categories",
+			[]interface{}{"water", "forest"},
+			geojson2svg.Style{Property: "v", Classifier: geojson2svg.Categorical(), Colors: []string{"a", "b"}},
+			geojson2svg.Legend{Corner: geojson2svg.BottomRight},
+			`<g class="legend">` +
+				`<rect x="328.800000" y="262.000000" width="12" height="12" fill="a"/>` +
+				`<text x="346.800000" y="268.000000" font-size="12" text-anchor="start" dominant-baseline="middle">forest</text>` +
+				`<rect x="328.800000" y="278.000000" width="12" height="12" fill="b"/>` +
+				`<text x="346.800000" y="284.000000" font-size="12" text-anchor="start" dominant-baseline="middle">water</text>` +
+				`</g>`},
+		{"escaped categories",
+			[]interface{}{"<b>"},
+			geojson2svg.Style{Property: "v", Classifier: geojson2svg.Categorical(), Colors: []string{"a"}},
+			geojson2svg.Legend{Corner: geojson2svg.BottomLeft, Title: "A & B"},
+			`<g class="legend">` +
+				`<text x="10.000000" y="268.000000" font-size="12" text-anchor="start" dominant-baseline="middle" font-weight="bold">A &amp; B</text>` +
+				`<rect x="10.000000" y="278.000000" width="12" height="12" fill="a"/>` +
+				`<text x="28.000000" y="284.000000" font-size="12" text-anchor="start" dominant-baseline="middle">&lt;b&gt;</text>` +
+				`</g>`},
+		{"continuous",
+			[]interface{}{0, 10},
+			geojson2svg.Style{Property: "v", Colors: []string{"#000", "#fff"}},
+			geojson2svg.Legend{Corner: geojson2svg.TopRight},
+			`<g class="legend">` +
+				`<defs><linearGradient id="legend-gradient"><stop offset="0.000000" stop-color="#000"/><stop offset="1.000000" stop-color="#fff"/></linearGradient></defs>` +
+				`<rect x="270.000000" y="10.000000" width="120" height="12" fill="url(#legend-gradient)"/>` +
+				`<text x="270.000000" y="32.000000" font-size="12" text-anchor="start" dominant-baseline="middle">0</text>` +
+				`<text x="390.000000" y="32.000000" font-size="12" text-anchor="end" dominant-baseline="middle">10</text>` +
+				`</g>`},
+		{"continuous without numeric values",
+			[]interface{}{"a", nil},
+			geojson2svg.Style{Property: "v", Colors: []string{"#000", "#fff"}},
+			geojson2svg.Legend{Title: "Values"},
+			``},
+	}
+
+	re := regexp.MustCompile(`<g class="legend".*</g>`)
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			svg := pointsWithProperty("v", tc.values...)
+			got := re.FindString(svg.Draw(400, 300, geojson2svg.WithStyle(tc.style), geojson2svg.WithLegend(tc.legend)))
+			if got != tc.expected {
+				tt.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestLegendWithoutStyle(t *testing.T) {
+	svg := geojson2svg.New()
+	got := svg.Draw(400, 400, geojson2svg.WithLegend(geojson2svg.Legend{Title: "Nothing"}))
+	if got != wantEmptySVG {
+		t.Errorf("expected %s, got %s", wantEmptySVG, got)
+	}
+}
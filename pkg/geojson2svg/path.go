@@ -0,0 +1,127 @@
+package geojson2svg
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// WithPrecision configures the SVG to print all numbers with at most the
+// given number of decimals, trailing zeros are trimmed. By default the
+// numbers are printed with six decimals.
+func WithPrecision(decimals int) Option {
+	return func(svg *SVG) {
+		svg.format.precision = decimals
+		svg.format.trim = true
+	}
+}
+
+// WithCompactPaths configures the SVG to encode the path data compactly
+// with relative line commands and without redundant separators, e.g.
+// "M10 20l5-3 2 4z" instead of "M10.000000 20.000000,15.000000 17.000000".
+// The numbers of compact path data are always trimmed, without
+// WithPrecision they have up to six decimals.
+func WithCompactPaths() Option {
+	return func(svg *SVG) {
+		svg.format.compact = true
+	}
+}
+
+// formatter formats the numbers and the path data of the svg.
+type formatter struct {
+	precision int
+	trim      bool
+	compact   bool
+}
+
+var defaultFormatter = formatter{precision: 6}
+
+func (f formatter) number(v float64) string {
+	s := strconv.FormatFloat(v, 'f', f.precision, 64)
+	if f.trim && strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// round rounds v to the precision of the formatter.
+func (f formatter) round(v float64) float64 {
+	s := math.Pow(10, float64(f.precision))
+	return math.Floor(v*s+0.5) / s
+}
+
+// path returns the path data of the parts. Closed parts are rings whose
+// first and last point are the same.
+func (f formatter) path(parts [][][]float64, closed bool) string {
+	if f.compact {
+		f.trim = true
+		return f.compactPath(parts, closed)
+	}
+	res := bytes.NewBufferString("")
+	for i, part := range parts {
+		if i > 0 {
+			res.WriteString(" ")
+		}
+		res.WriteString("M")
+		for j, p := range part {
+			if j > 0 {
+				res.WriteString(",")
+			}
+			res.WriteString(f.number(p[0]) + " " + f.number(p[1]))
+		}
+	}
+	if closed {
+		res.WriteString(" Z")
+	}
+	return res.String()
+}
+
+func (f formatter) compactPath(parts [][][]float64, closed bool) string {
+	res := bytes.NewBufferString("")
+	for _, part := range parts {
+		if len(part) == 0 {
+			continue
+		}
+		if closed && len(part) > 1 && equalPoints(part[0], part[len(part)-1]) {
+			part = part[:len(part)-1]
+		}
+		x, y := f.round(part[0][0]), f.round(part[0][1])
+		res.WriteString("M")
+		f.appendNumbers(res, x, y)
+		for i, p := range part[1:] {
+			if i == 0 {
+				res.WriteString("l")
+			}
+			nx, ny := f.round(p[0]), f.round(p[1])
+			f.appendNumbers(res, nx-x, ny-y)
+			x, y = nx, ny
+		}
+		if closed {
+			res.WriteString("z")
+		}
+	}
+	return res.String()
+}
+
+// appendNumbers appends the numbers to the path data, separated only where
+// necessary.
+func (f formatter) appendNumbers(res *bytes.Buffer, vs ...float64) {
+	for _, v := range vs {
+		s := f.number(v)
+		if b := res.Bytes(); len(b) > 0 && s[0] != '-' && (b[len(b)-1] == '.' || b[len(b)-1] >= '0' && b[len(b)-1] <= '9') {
+			res.WriteString(" ")
+		}
+		res.WriteString(s)
+	}
+}
+
+// length formats a length such as a radius, trailing zeros are always
+// trimmed.
+func (f formatter) length(v float64) string {
+	f.trim = true
+	return f.number(v)
+}
@@ -0,0 +1,88 @@
+package geojson2svg_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/fapian/geojson2svg/pkg/geojson2svg"
+)
+
+func TestPathFormat(t *testing.T) {
+	tcs := []struct {
+		name     string
+		geometry string
+		opts     []geojson2svg.Option
+		expected string
+	}{
+		{"default",
+			`{"type": "LineString", "coordinates": [[0.5,200], [10.25,190.125], [20,200]]}`,
+			nil,
+			`<path d="M0.500000 200.000000,10.250000 209.875000,20.000000 200.000000"/>`},
+		{"precision",
+			`{"type": "LineString", "coordinates": [[0.5,200], [10.25,190.125], [20,200]]}`,
+			[]geojson2svg.Option{geojson2svg.WithPrecision(2)},
+			`<path d="M0.5 200,10.25 209.88,20 200"/>`},
+		{"zero precision",
+			`{"type": "LineString", "coordinates": [[0.5,200], [10.25,190.125], [20,200]]}`,
+			[]geojson2svg.Option{geojson2svg.WithPrecision(0)},
+			`<path d="M0 200,10 210,20 200"/>`},
+		{"compact line",
+			`{"type": "LineString", "coordinates": [[0.5,200], [10.25,190.125], [20,200]]}`,
+			[]geojson2svg.Option{geojson2svg.WithPrecision(2), geojson2svg.WithCompactPaths()},
+			`<path d="M0.5 200l9.75 9.88 9.75-9.88"/>`},
+		{"compact polygon with hole",
+			`{"type": "Polygon", "coordinates": [[[0,0], [400,0], [400,400], [0,400], [0,0]], [[100,100], [100,300], [300,300], [100,100]]]}`,
+			[]geojson2svg.Option{geojson2svg.WithPrecision(1), geojson2svg.WithCompactPaths()},
+			`<path d="M0 400l400 0 0-400-400 0zM100 300l0-200 200 0z"/>`},
+		{"compact multilinestring",
+			`{"type": "MultiLineString", "coordinates": [[[0,0], [10,10]], [[20,20], [30,30]]]}`,
+			[]geojson2svg.Option{geojson2svg.WithPrecision(1), geojson2svg.WithCompactPaths()},
+			`<path d="M0 400l10-10"/><path d="M20 380l10-10"/>`},
+		{"compact without precision",
+			`{"type": "LineString", "coordinates": [[0,0], [100,100], [100.125,0]]}`,
+			[]geojson2svg.Option{geojson2svg.WithCompactPaths()},
+			`<path d="M0 400l100-100 0.125 100"/>`},
+		{"empty linestring",
+			`{"type": "LineString", "coordinates": []}`,
+			[]geojson2svg.Option{geojson2svg.WithCompactPaths()},
+			``},
+		{"empty part of a multilinestring",
+			`{"type": "MultiLineString", "coordinates": [[], [[0,0], [10,10]]]}`,
+			[]geojson2svg.Option{geojson2svg.WithPrecision(1), geojson2svg.WithCompactPaths()},
+			`<path d="M0 400l10-10"/>`},
+		{"empty polygon",
+			`{"type": "Polygon", "coordinates": [[]]}`,
+			[]geojson2svg.Option{geojson2svg.WithCompactPaths()},
+			``},
+	}
+
+	re := regexp.MustCompile(`<path.*/>`)
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			svg := geojson2svg.New()
+			// fixes the scale to 1:1
+			if err := svg.AddGeometry(`{"type": "MultiPoint", "coordinates": [[0,0], [400,400]]}`); err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			if err := svg.AddGeometry(tc.geometry); err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			got := re.FindString(svg.Draw(400, 400, tc.opts...))
+			if got != tc.expected {
+				tt.Errorf("expected %s\ngot %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestPrecisionRootAndPoints(t *testing.T) {
+	svg := geojson2svg.New()
+	if err := svg.AddGeometry(`{"type": "MultiPoint", "coordinates": [[0,0], [400,400]]}`); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	expected := `<svg width="400" height="400"><circle cx="0" cy="400" r="1"/><circle cx="400" cy="0" r="1"/></svg>`
+	got := svg.Draw(400, 400, geojson2svg.WithPrecision(3))
+	if got != expected {
+		t.Errorf("expected %s\ngot %s", expected, got)
+	}
+}
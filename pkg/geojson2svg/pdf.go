@@ -0,0 +1,324 @@
+package geojson2svg
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// DrawPDF renders the svg with the given options as single page PDF
+// document of width x height points to w. The geometries are scaled,
+// projected, clipped and simplified like Draw does and painted with their
+// fill, stroke, stroke-width, opacity, fill-opacity and stroke-opacity
+// attributes, which are inherited from the layers and the svg root. Labels
+// and legends are set in Helvetica, which supports the Latin-1 characters
+// only. Stylesheets are not applied and points with a symbol are drawn as
+// circles.
+func (svg *SVG) DrawPDF(w io.Writer, width, height float64, opts ...Option) error {
+	if err := svg.apply(opts); err != nil {
+		return err
+	}
+	r := newPDFRenderer(width, height, svg.attributes)
+	if err := svg.render(r, width, height); err != nil {
+		return err
+	}
+	return r.writeTo(w)
+}
+
+// pdfRenderer renders the elements into the content stream of a PDF page.
+// The coordinate system of the page is flipped, so the elements are drawn
+// in the coordinate system of the svg.
+type pdfRenderer struct {
+	width, height float64
+	content       *bytes.Buffer
+	states        []pdfState
+	alpha         [2]float64
+	alphas        []string
+	format        formatter
+}
+
+// pdfState is the state of a group, saved is set if the graphics state was
+// saved at its start.
+type pdfState struct {
+	paint
+	saved bool
+	alpha [2]float64
+}
+
+// pdfCircle is the distance of the control points of the bezier curves
+// approximating a quarter circle.
+const pdfCircle = 0.5522847498
+
+func newPDFRenderer(width, height float64, attrs map[string]string) *pdfRenderer {
+	r := &pdfRenderer{
+		width:   width,
+		height:  height,
+		content: bytes.NewBufferString(""),
+		states:  []pdfState{{paint: defaultPaint.with(attrs)}},
+		alpha:   [2]float64{1, 1},
+		format:  formatter{precision: 3, trim: true},
+	}
+	r.printf("1 0 0 -1 0 %s cm 1 j\n", r.format.number(height))
+	return r
+}
+
+func (r *pdfRenderer) printf(format string, args ...interface{}) {
+	fmt.Fprintf(r.content, format, args...)
+}
+
+// numbers formats the numbers separated by spaces.
+func (r *pdfRenderer) numbers(vs ...float64) string {
+	s := make([]string, len(vs))
+	for i, v := range vs {
+		s[i] = r.format.number(v)
+	}
+	return strings.Join(s, " ")
+}
+
+func (r *pdfRenderer) current() pdfState {
+	return r.states[len(r.states)-1]
+}
+
+func (r *pdfRenderer) group(attrs map[string]string, title, desc string) {
+	r.states = append(r.states, pdfState{paint: r.current().with(attrs)})
+}
+
+func (r *pdfRenderer) end() {
+	s := r.current()
+	r.states = r.states[:len(r.states)-1]
+	if s.saved {
+		r.printf("Q\n")
+		r.alpha = s.alpha
+	}
+}
+
+func (r *pdfRenderer) defs(css string, ps *pointStyling) {}
+
+func (r *pdfRenderer) clip(x, y, width, height float64) {
+	r.states = append(r.states, pdfState{paint: r.current().paint, saved: true, alpha: r.alpha})
+	r.printf("q %s re W n\n", r.numbers(x, y, width, height))
+}
+
+func (r *pdfRenderer) circle(x, y, radius float64, attrs map[string]string) {
+	k := radius * pdfCircle
+	r.draw(attrs, true, func() {
+		r.printf("%s m\n", r.numbers(x+radius, y))
+		r.printf("%s c\n", r.numbers(x+radius, y+k, x+k, y+radius, x, y+radius))
+		r.printf("%s c\n", r.numbers(x-k, y+radius, x-radius, y+k, x-radius, y))
+		r.printf("%s c\n", r.numbers(x-radius, y-k, x-k, y-radius, x, y-radius))
+		r.printf("%s c h\n", r.numbers(x+k, y-radius, x+radius, y-k, x+radius, y))
+	})
+}
+
+func (r *pdfRenderer) rect(x, y, width, height float64, attrs map[string]string) {
+	r.draw(attrs, true, func() {
+		r.printf("%s re\n", r.numbers(x, y, width, height))
+	})
+}
+
+func (r *pdfRenderer) path(parts [][][]float64, closed bool, attrs map[string]string) {
+	r.draw(attrs, closed, func() {
+		for _, part := range parts {
+			for i, p := range part {
+				op := "l"
+				if i == 0 {
+					op = "m"
+				}
+				r.printf("%s %s\n", r.numbers(p[0], p[1]), op)
+			}
+			if closed {
+				r.printf("h\n")
+			}
+		}
+	})
+}
+
+// draw paints the path written by path with the fill and stroke of the
+// attributes. Like in SVG open paths are filled as if they were closed.
+func (r *pdfRenderer) draw(attrs map[string]string, closed bool, path func()) {
+	p := r.current().with(attrs)
+	fill, filled := parseColor(p.fill)
+	stroke, stroked := parseColor(p.stroke)
+	stroked = stroked && p.strokeWidth > 0
+	if !filled && !stroked {
+		return
+	}
+	r.setAlpha(p.opacity*p.fillOpacity, p.opacity*p.strokeOpacity)
+	op := "S"
+	if filled {
+		r.printf("%s rg\n", r.numbers(float64(fill.R)/0xff, float64(fill.G)/0xff, float64(fill.B)/0xff))
+		op = "f"
+	}
+	if stroked {
+		r.printf("%s RG %s w\n", r.numbers(float64(stroke.R)/0xff, float64(stroke.G)/0xff, float64(stroke.B)/0xff),
+			r.format.number(p.strokeWidth))
+		if filled {
+			op = "B"
+		}
+	}
+	path()
+	r.printf("%s\n", op)
+}
+
+// setAlpha sets the fill and stroke opacity of the graphics state.
+func (r *pdfRenderer) setAlpha(fill, stroke float64) {
+	alpha := [2]float64{fill, stroke}
+	if alpha == r.alpha {
+		return
+	}
+	r.alpha = alpha
+	state := fmt.Sprintf("<< /Type /ExtGState /ca %s /CA %s >>", r.format.number(fill), r.format.number(stroke))
+	for i, s := range r.alphas {
+		if s == state {
+			r.printf("/GS%d gs\n", i)
+			return
+		}
+	}
+	r.alphas = append(r.alphas, state)
+	r.printf("/GS%d gs\n", len(r.alphas)-1)
+}
+
+func (r *pdfRenderer) symbol(id string, x, y, size float64, attrs map[string]string) {
+	r.circle(x+size/2, y+size/2, size/2, attrs)
+}
+
+func (r *pdfRenderer) text(x, y float64, s string, fontSize float64, anchor string, attrs map[string]string) {
+	w := textWidth(s, fontSize)
+	switch anchor {
+	case "middle":
+		x -= w / 2
+	case "end":
+		x -= w
+	}
+	r.drawText(s, fontSize, attrs, 1, 0, x, y+0.35*fontSize)
+}
+
+func (r *pdfRenderer) textAlong(line [][]float64, s string, fontSize float64, attrs map[string]string) {
+	length := 0.0
+	for i := 1; i < len(line); i++ {
+		length += math.Hypot(line[i][0]-line[i-1][0], line[i][1]-line[i-1][1])
+	}
+	// the baseline of the text is centered on the middle of the line
+	half := length / 2
+	for i := 1; i < len(line); i++ {
+		p, q := line[i-1], line[i]
+		l := math.Hypot(q[0]-p[0], q[1]-p[1])
+		if l < half || l == 0 {
+			half -= l
+			continue
+		}
+		cos, sin := (q[0]-p[0])/l, (q[1]-p[1])/l
+		x, y := p[0]+cos*half, p[1]+sin*half
+		w := textWidth(s, fontSize) / 2
+		r.drawText(s, fontSize, attrs, cos, sin, x-cos*w, y-sin*w)
+		return
+	}
+}
+
+// drawText draws the text with its baseline starting at x, y in the
+// direction cos, sin.
+func (r *pdfRenderer) drawText(s string, fontSize float64, attrs map[string]string, cos, sin, x, y float64) {
+	p := r.current().with(attrs)
+	fill, ok := parseColor(p.fill)
+	if !ok {
+		return
+	}
+	font := "F1"
+	if attrs["font-weight"] == "bold" {
+		font = "F2"
+	}
+	r.setAlpha(p.opacity*p.fillOpacity, r.alpha[1])
+	r.printf("BT /%s %s Tf %s rg %s Tm (%s) Tj ET\n", font, r.format.number(fontSize),
+		r.numbers(float64(fill.R)/0xff, float64(fill.G)/0xff, float64(fill.B)/0xff),
+		r.numbers(cos, sin, sin, -cos, x, y), pdfString(s))
+}
+
+func (r *pdfRenderer) gradient(x, y, width, height float64, colors []string) {
+	drawGradientSteps(r, x, y, width, height, colors)
+}
+
+func (r *pdfRenderer) legend(l Legend, st *styling, width, height float64) {
+	drawLegend(r, l, st, width, height)
+}
+
+func (r *pdfRenderer) err() error {
+	return nil
+}
+
+// pdfString returns s as content of a PDF string literal in the
+// WinAnsiEncoding, characters outside of Latin-1 are replaced by "?".
+func pdfString(s string) string {
+	res := bytes.NewBufferString("")
+	for _, c := range s {
+		switch {
+		case c == '\\' || c == '(' || c == ')':
+			res.WriteByte('\\')
+			res.WriteByte(byte(c))
+		case c < 0x20 || 0x7f <= c && c < 0xa0 || c > 0xff:
+			res.WriteByte('?')
+		default:
+			res.WriteByte(byte(c))
+		}
+	}
+	return res.String()
+}
+
+// writeTo writes the PDF document with the content to w.
+func (r *pdfRenderer) writeTo(w io.Writer) error {
+	stream := bytes.NewBufferString("")
+	zw := zlib.NewWriter(stream)
+	// writing to a bytes.Buffer never fails
+	_, _ = zw.Write(r.content.Bytes())
+	_ = zw.Close()
+
+	states := bytes.NewBufferString("")
+	for i, s := range r.alphas {
+		fmt.Fprintf(states, " /GS%d %s", i, s)
+	}
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s] /Contents 4 0 R "+
+			"/Resources << /Font << /F1 5 0 R /F2 6 0 R >> /ExtGState <<%s >> >> >>",
+			r.numbers(r.width, r.height), states),
+		fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", stream.Len(), stream),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	}
+
+	bw := bufio.NewWriter(w)
+	ew := &errWriter{w: bw}
+	cw := &countingWriter{w: ew}
+	fmt.Fprint(cw, "%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, o := range objects {
+		offsets[i] = cw.n
+		fmt.Fprintf(cw, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+	xref := cw.n
+	fmt.Fprintf(cw, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, o := range offsets {
+		fmt.Fprintf(cw, "%010d 00000 n \n", o)
+	}
+	fmt.Fprintf(cw, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	if ew.err != nil {
+		return ew.err
+	}
+	return bw.Flush()
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += n
+	return n, err
+}
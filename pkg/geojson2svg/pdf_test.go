@@ -0,0 +1,118 @@
+package geojson2svg_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/fapian/geojson2svg/pkg/geojson2svg"
+)
+
+// pdfContent checks the cross reference table of the PDF document and
+// returns its decompressed content stream.
+func pdfContent(t *testing.T, doc []byte) string {
+	if !bytes.HasPrefix(doc, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(doc, []byte("%%EOF\n")) {
+		t.Fatalf("invalid pdf header or trailer")
+	}
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(doc)
+	if m == nil {
+		t.Fatalf("missing startxref")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(doc[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref does not point to the xref table")
+	}
+	for i, o := range regexp.MustCompile(`(\d{10}) 00000 n \n`).FindAllSubmatch(doc[xref:], -1) {
+		offset, _ := strconv.Atoi(string(o[1]))
+		if !bytes.HasPrefix(doc[offset:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))) {
+			t.Fatalf("invalid offset of object %d", i+1)
+		}
+	}
+
+	s := regexp.MustCompile(`(?s)/Length (\d+) /Filter /FlateDecode >>\nstream\n`).FindSubmatchIndex(doc)
+	if s == nil {
+		t.Fatalf("missing content stream")
+	}
+	length, _ := strconv.Atoi(string(doc[s[2]:s[3]]))
+	zr, err := zlib.NewReader(bytes.NewReader(doc[s[1] : s[1]+length]))
+	if err != nil {
+		t.Fatalf("invalid content stream %v", err)
+	}
+	content, err := ioutil.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("invalid content stream %v", err)
+	}
+	return string(content)
+}
+
+func TestDrawPDF(t *testing.T) {
+	tcs := []struct {
+		name     string
+		feature  string
+		opts     []geojson2svg.Option
+		expected []string
+	}{
+		{"filled polygon",
+			`{"type": "Feature", "geometry": {"type": "Polygon", "coordinates": [[[0,0], [100,0], [100,100], [0,0]]]}, "properties": {"fill": "red"}}`,
+			[]geojson2svg.Option{geojson2svg.UseProperties([]string{"fill"})},
+			[]string{"1 0 0 -1 0 100 cm", "1 0 0 rg\n0 100 m\n100 100 l\n100 0 l\n0 100 l\nh\nf\n"}},
+		{"stroked line with opacity",
+			`{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[0,0], [100,100]]},
+				"properties": {"fill": "none", "stroke": "#0000ff", "stroke-width": "2", "opacity": "0.5"}}`,
+			[]geojson2svg.Option{geojson2svg.UseProperties([]string{"fill", "stroke", "stroke-width", "opacity"})},
+			[]string{"/GS0 gs\n0 0 1 RG 2 w\n0 100 m\n100 0 l\nS\n"}},
+		{"point",
+			`{"type": "Feature", "geometry": {"type": "MultiPoint", "coordinates": [[0,0], [100,100]]}, "properties": {}}`,
+			[]geojson2svg.Option{geojson2svg.WithPoints(geojson2svg.PointStyle{Radius: 2})},
+			[]string{"2 100 m\n2 101.105 1.105 102 0 102 c\n"}},
+		{"clipped to bounds",
+			`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0,0]}, "properties": {}}`,
+			[]geojson2svg.Option{geojson2svg.WithBounds(0, 0, 100, 100)},
+			[]string{"q 0 0 100 100 re W n\n", "Q\n"}},
+		{"label",
+			`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0,0]}, "properties": {"name": "Köln (Cologne)"}}`,
+			[]geojson2svg.Option{geojson2svg.WithLabels("name"), geojson2svg.WithBounds(0, 0, 100, 100)},
+			[]string{"BT /F1 12 Tf 0 0 0 rg 1 0 0 -1 6 104.2 Tm (K\xf6ln \\(Cologne\\)) Tj ET\n"}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			svg := geojson2svg.New()
+			if err := svg.AddFeature(tc.feature); err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			buf := &bytes.Buffer{}
+			if err := svg.DrawPDF(buf, 100, 100, tc.opts...); err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			content := pdfContent(tt, buf.Bytes())
+			for _, e := range tc.expected {
+				if !strings.Contains(content, e) {
+					tt.Errorf("expected %q in\n%s", e, content)
+				}
+			}
+		})
+	}
+}
+
+func TestDrawPDFLegend(t *testing.T) {
+	svg := pointsWithProperty("kind", "a", "b")
+	buf := &bytes.Buffer{}
+	err := svg.DrawPDF(buf, 200, 200,
+		geojson2svg.WithStyle(geojson2svg.Style{Property: "kind", Classifier: geojson2svg.Categorical(), Colors: []string{"red", "blue"}}),
+		geojson2svg.WithLegend(geojson2svg.Legend{Title: "Kind"}))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	content := pdfContent(t, buf.Bytes())
+	for _, e := range []string{"(Kind) Tj", "/F2 12 Tf", "1 0 0 rg\n10 26 12 12 re\nf\n", "(a) Tj", "(b) Tj"} {
+		if !strings.Contains(content, e) {
+			t.Errorf("expected %q in\n%s", e, content)
+		}
+	}
+}
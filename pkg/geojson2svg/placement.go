@@ -0,0 +1,151 @@
+package geojson2svg
+
+import (
+	"math"
+	"sort"
+)
+
+// labelMargin is the minimal distance between two labels.
+const labelMargin = 2
+
+// placement is the position of a label.
+type placement struct {
+	x, y       float64
+	textAnchor string
+}
+
+// box is an axis aligned rectangle.
+type box struct {
+	minX, minY, maxX, maxY float64
+}
+
+func (b box) intersects(o box) bool {
+	return b.minX < o.maxX && o.minX < b.maxX && b.minY < o.maxY && o.minY < b.maxY
+}
+
+func (b box) within(o box) bool {
+	return b.minX >= o.minX && b.maxX <= o.maxX && b.minY >= o.minY && b.maxY <= o.maxY
+}
+
+// candidates returns the possible placements of the label, the preferred
+// placement first.
+func candidates(lb label, l *labels) []placement {
+	a, fs := lb.anchor, l.fontSize
+	switch {
+	case a.kind == pointAnchor:
+		d := fs / 2
+		return []placement{
+			{a.x + d, a.y, "start"},
+			{a.x + d, a.y - fs, "start"},
+			{a.x + d, a.y + fs, "start"},
+			{a.x - d, a.y, "end"},
+			{a.x - d, a.y - fs, "end"},
+			{a.x - d, a.y + fs, "end"},
+			{a.x, a.y - fs, "middle"},
+			{a.x, a.y + fs, "middle"},
+		}
+	case a.kind == lineAnchor && l.alongLines:
+		return []placement{{a.x, a.y, "middle"}}
+	}
+	return []placement{
+		{a.x, a.y, "middle"},
+		{a.x, a.y - fs, "middle"},
+		{a.x, a.y + fs, "middle"},
+	}
+}
+
+// extent estimates the box covered by the label at p.
+func extent(text string, p placement, fontSize float64) box {
+	w := textWidth(text, fontSize)
+	minX := p.x - w/2
+	switch p.textAnchor {
+	case "start":
+		minX = p.x
+	case "end":
+		minX = p.x - w
+	}
+	return box{minX, p.y - fontSize/2, minX + w, p.y + fontSize/2}
+}
+
+// placeLabels places the labels in the order of their priority at the first
+// candidate position which does not collide with an already placed label.
+// Labels which can not be placed are dropped. The placed labels are
+// returned in their original order.
+func placeLabels(ls []label, l *labels, width, height float64) []label {
+	order := make([]int, len(ls))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return ls[order[i]].priority > ls[order[j]].priority
+	})
+
+	canvas := box{0, 0, width, height}
+	placed := newBoxGrid(4 * l.fontSize)
+	keep := make([]bool, len(ls))
+	for _, i := range order {
+		lb := ls[i]
+		if lb.anchor.kind == lineAnchor && l.alongLines && textWidth(lb.text, l.fontSize) > lineLength(lb.anchor.line) {
+			continue
+		}
+		for _, p := range candidates(lb, l) {
+			b := extent(lb.text, p, l.fontSize)
+			if !b.within(canvas) || placed.collides(b) {
+				continue
+			}
+			placed.insert(box{b.minX - labelMargin, b.minY - labelMargin, b.maxX + labelMargin, b.maxY + labelMargin})
+			ls[i].placement = p
+			keep[i] = true
+			break
+		}
+	}
+
+	res := []label{}
+	for i, lb := range ls {
+		if keep[i] {
+			res = append(res, lb)
+		}
+	}
+	return res
+}
+
+// boxGrid is a uniform grid of boxes to find colliding boxes quickly.
+type boxGrid struct {
+	size  float64
+	cells map[[2]int][]box
+}
+
+func newBoxGrid(size float64) *boxGrid {
+	return &boxGrid{size: size, cells: make(map[[2]int][]box)}
+}
+
+func (g *boxGrid) each(b box, fn func(k [2]int) bool) {
+	for x := int(math.Floor(b.minX / g.size)); x <= int(math.Floor(b.maxX/g.size)); x++ {
+		for y := int(math.Floor(b.minY / g.size)); y <= int(math.Floor(b.maxY/g.size)); y++ {
+			if !fn([2]int{x, y}) {
+				return
+			}
+		}
+	}
+}
+
+func (g *boxGrid) insert(b box) {
+	g.each(b, func(k [2]int) bool {
+		g.cells[k] = append(g.cells[k], b)
+		return true
+	})
+}
+
+func (g *boxGrid) collides(b box) bool {
+	collides := false
+	g.each(b, func(k [2]int) bool {
+		for _, o := range g.cells[k] {
+			if o.intersects(b) {
+				collides = true
+				return false
+			}
+		}
+		return true
+	})
+	return collides
+}
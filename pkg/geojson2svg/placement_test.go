@@ -0,0 +1,102 @@
+package geojson2svg_test
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/fapian/geojson2svg/pkg/geojson2svg"
+	geojson "github.com/paulmach/go.geojson"
+)
+
+type namedPoint struct {
+	name     string
+	priority interface{}
+	x, y     float64
+}
+
+// labeledPoints returns a svg with the points and its scale fixed to 1:1 on
+// a 400x400 canvas.
+func labeledPoints(ps ...namedPoint) *geojson2svg.SVG {
+	svg := geojson2svg.New()
+	svg.AddGeometryObject(geojson.NewMultiPointGeometry([]float64{0, 0}, []float64{400, 400}))
+	for _, p := range ps {
+		f := geojson.NewPointFeature([]float64{p.x, p.y})
+		f.SetProperty("name", p.name)
+		if p.priority != nil {
+			f.SetProperty("population", p.priority)
+		}
+		svg.AddFeatureObject(f)
+	}
+	return svg
+}
+
+func texts(svg string) []string {
+	return regexp.MustCompile(`<text[^>]*>[^<]*</text>`).FindAllString(svg, -1)
+}
+
+func text(x, y float64, anchor, name string) string {
+	return fmt.Sprintf(`<text x="%f" y="%f" font-size="12" text-anchor="%s" dominant-baseline="middle">%s</text>`, x, y, anchor, name)
+}
+
+func TestLabelPlacement(t *testing.T) {
+	tcs := []struct {
+		name     string
+		points   []namedPoint
+		opts     []geojson2svg.LabelOption
+		expected []string
+	}{
+		{"overlapping without collision detection",
+			[]namedPoint{{"Alpha", 10, 100, 200}, {"Beta", 100, 104, 200}},
+			nil,
+			[]string{text(106, 200, "start", "Alpha"), text(110, 200, "start", "Beta")}},
+		{"priority wins",
+			[]namedPoint{{"Alpha", 10, 100, 200}, {"Beta", 100, 104, 200}},
+			[]geojson2svg.LabelOption{geojson2svg.AvoidLabelCollisions(), geojson2svg.LabelPriority("population")},
+			[]string{text(94, 200, "end", "Alpha"), text(110, 200, "start", "Beta")}},
+		{"feature order without priority",
+			[]namedPoint{{"Alpha", 10, 100, 200}, {"Beta", 100, 104, 200}},
+			[]geojson2svg.LabelOption{geojson2svg.AvoidLabelCollisions()},
+			[]string{text(106, 200, "start", "Alpha"), text(98, 200, "end", "Beta")}},
+		{"labels without priority come last",
+			[]namedPoint{{"Alpha", nil, 100, 200}, {"Beta", "many", 104, 200}, {"Gamma", 1, 104, 200}},
+			[]geojson2svg.LabelOption{geojson2svg.AvoidLabelCollisions(), geojson2svg.LabelPriority("population")},
+			[]string{text(94, 200, "end", "Alpha"), text(110, 200, "start", "Gamma")}},
+		{"colliding labels are dropped",
+			[]namedPoint{{"Same", nil, 200, 200}, {"Same", nil, 200, 200}, {"Same", nil, 200, 200}},
+			[]geojson2svg.LabelOption{geojson2svg.AvoidLabelCollisions()},
+			[]string{text(206, 200, "start", "Same"), text(194, 200, "end", "Same")}},
+		{"labels are kept inside the svg",
+			[]namedPoint{{"East", nil, 400, 200}, {"North", nil, 200, 400}},
+			[]geojson2svg.LabelOption{geojson2svg.AvoidLabelCollisions()},
+			[]string{text(394, 200, "end", "East"), text(206, 12, "start", "North")}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			svg := labeledPoints(tc.points...)
+			got := texts(svg.Draw(400, 400, geojson2svg.WithLabels("name", tc.opts...)))
+			if !reflect.DeepEqual(got, tc.expected) {
+				tt.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestLabelPlacementAlongLines(t *testing.T) {
+	svg := geojson2svg.New()
+	err := svg.AddFeatureCollection(`{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "properties": {"name": "Long Road"}, "geometry": {"type": "LineString", "coordinates": [[0,0], [400,0]]}},
+		{"type": "Feature", "properties": {"name": "A very long name for a short road"}, "geometry": {"type": "LineString", "coordinates": [[0,200], [10,200]]}}
+	]}`)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	got := svg.Draw(400, 400, geojson2svg.WithLabels("name", geojson2svg.LabelsAlongLines(), geojson2svg.AvoidLabelCollisions()))
+	want := []string{`<text font-size="12"><textPath href="#label-path-0" xlink:href="#label-path-0" startOffset="50%" text-anchor="middle">Long Road</textPath></text>`}
+	if got := regexp.MustCompile(`<text.*?</text>`).FindAllString(got, -1); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,128 @@
+package geojson2svg
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	geojson "github.com/paulmach/go.geojson"
+)
+
+// A Shape is the shape points are drawn with.
+type Shape int
+
+// The shapes of the points.
+const (
+	Circle Shape = iota
+	Square
+	Triangle
+	Star
+)
+
+// PointStyle represents how the points of the SVG are drawn.
+type PointStyle struct {
+	// Shape is the shape of the points. Defaults to Circle.
+	Shape Shape
+	// Radius is the radius of the points in pixels. For squares it is half
+	// of the side length. Defaults to 1.
+	Radius float64
+	// Property is the name of a numeric feature property. If it is set the
+	// points are drawn as proportional symbols: the area of a point is
+	// proportional to the value and the largest value is drawn with Radius.
+	// Points without a numeric value are not drawn.
+	Property string
+	// Symbol is the content of a symbol which is drawn for every point
+	// instead of the shape. The symbol uses the coordinate system
+	// (-1 -1, 1 1) and is scaled to the radius of the point.
+	Symbol string
+}
+
+// pointSymbolID is the id of the symbol element in the defs, symbols of
+// layers are prefixed with the id of the layer.
+const pointSymbolID = "point-symbol"
+
+// WithPoints configures the SVG to draw the points according to p.
+func WithPoints(p PointStyle) Option {
+	return func(svg *SVG) {
+		svg.pointStyle = &p
+	}
+}
+
+// pointStyling is a point style applied to a set of features.
+type pointStyling struct {
+	PointStyle
+	max float64
+	id  string
+}
+
+func newPointStyling(p *PointStyle, fs []*geojson.Feature, id string) *pointStyling {
+	ps := &pointStyling{PointStyle: PointStyle{Radius: 1}, id: id}
+	if p == nil {
+		return ps
+	}
+	ps.PointStyle = *p
+	if ps.Radius <= 0 {
+		ps.Radius = 1
+	}
+	if ps.Property == "" {
+		return ps
+	}
+	for _, f := range fs {
+		if v, ok := toFloat(f.Properties[ps.Property]); ok {
+			ps.max = math.Max(ps.max, math.Abs(v))
+		}
+	}
+	return ps
+}
+
+// radius returns the radius of the points of a feature with the given
+// properties, 0 means the points are not drawn.
+func (ps *pointStyling) radius(props map[string]interface{}) float64 {
+	if ps.Property == "" {
+		return ps.Radius
+	}
+	v, ok := toFloat(props[ps.Property])
+	if !ok || ps.max == 0 {
+		return 0
+	}
+	return ps.Radius * math.Sqrt(math.Abs(v)/ps.max)
+}
+
+// drawSymbol draws the definition of the symbol if there is one.
+func (ps *pointStyling) drawSymbol(w io.Writer) {
+	if ps.Symbol == "" {
+		return
+	}
+	fmt.Fprintf(w, `<defs><symbol id="%s" viewBox="-1 -1 2 2">%s</symbol></defs>`, escapeText(ps.id), ps.Symbol)
+}
+
+// shape draws the point shape with radius r at x, y.
+func (ps *pointStyling) shape(rd renderer, x, y, r float64, attrs map[string]string) {
+	switch {
+	case ps.Symbol != "":
+		rd.symbol(ps.id, x-r, y-r, 2*r, attrs)
+	case ps.Shape == Square:
+		rd.rect(x-r, y-r, 2*r, 2*r, attrs)
+	case ps.Shape == Triangle:
+		rd.path([][][]float64{regularRing(x, y, r, r, 3)}, true, attrs)
+	case ps.Shape == Star:
+		rd.path([][][]float64{regularRing(x, y, r, 0.4*r, 10)}, true, attrs)
+	default:
+		rd.circle(x, y, r, attrs)
+	}
+}
+
+// regularRing returns a closed ring of n vertices around x, y pointing
+// upwards. The vertices alternate between the radii r0 and r1.
+func regularRing(x, y, r0, r1 float64, n int) [][]float64 {
+	ring := make([][]float64, 0, n+1)
+	for i := 0; i < n; i++ {
+		r := r0
+		if i%2 == 1 {
+			r = r1
+		}
+		a := -math.Pi/2 + 2*math.Pi*float64(i)/float64(n)
+		ring = append(ring, []float64{x + r*math.Cos(a), y + r*math.Sin(a)})
+	}
+	return append(ring, ring[0])
+}
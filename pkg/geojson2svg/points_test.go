@@ -0,0 +1,108 @@
+package geojson2svg_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/fapian/geojson2svg/pkg/geojson2svg"
+)
+
+func TestPoints(t *testing.T) {
+	tcs := []struct {
+		name     string
+		points   geojson2svg.PointStyle
+		expected string
+	}{
+		{"default circle",
+			geojson2svg.PointStyle{},
+			`<circle cx="0" cy="100" r="1"/><circle cx="100" cy="0" r="1"/>`},
+		{"fixed radius",
+			geojson2svg.PointStyle{Radius: 2.5},
+			`<circle cx="0" cy="100" r="2.5"/><circle cx="100" cy="0" r="2.5"/>`},
+		{"proportional radius",
+			geojson2svg.PointStyle{Radius: 10, Property: "population"},
+			`<circle cx="0" cy="100" r="5"/><circle cx="100" cy="0" r="10"/>`},
+		{"square",
+			geojson2svg.PointStyle{Shape: geojson2svg.Square, Radius: 2},
+			`<rect x="-2" y="98" width="4" height="4"/><rect x="98" y="-2" width="4" height="4"/>`},
+		{"triangle",
+			geojson2svg.PointStyle{Shape: geojson2svg.Triangle, Radius: 2},
+			`<path d="M0 98,1.73 101,-1.73 101,0 98 Z"/><path d="M100 -2,101.73 1,98.27 1,100 -2 Z"/>`},
+		{"symbol",
+			geojson2svg.PointStyle{Radius: 3, Symbol: `<circle r="1"/>`},
+			`<defs><symbol id="point-symbol" viewBox="-1 -1 2 2"><circle r="1"/></symbol></defs>` +
+				`<use href="#point-symbol" xlink:href="#point-symbol" x="-3" y="97" width="6" height="6"/><use href="#point-symbol" xlink:href="#point-symbol" x="97" y="-3" width="6" height="6"/>`},
+	}
+
+	re := regexp.MustCompile(`^<svg[^>]*>(.*)</svg>$`)
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			svg := geojson2svg.New()
+			err := svg.AddFeatureCollection(`{"type": "FeatureCollection", "features": [
+				{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0,0]}, "properties": {"population": 100}},
+				{"type": "Feature", "geometry": {"type": "Point", "coordinates": [100,100]}, "properties": {"population": 400}}
+			]}`)
+			if err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			got := re.FindStringSubmatch(svg.Draw(100, 100, geojson2svg.WithPrecision(2), geojson2svg.WithPoints(tc.points)))
+			if len(got) != 2 || got[1] != tc.expected {
+				tt.Errorf("expected %s\ngot %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestPointsWithoutValue(t *testing.T) {
+	svg := geojson2svg.New()
+	err := svg.AddFeatureCollection(`{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0,0]}, "properties": {"population": 100}},
+		{"type": "Feature", "geometry": {"type": "Point", "coordinates": [100,100]}, "properties": {"population": "unknown"}}
+	]}`)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	expected := `<svg width="100.000000" height="100.000000"><circle cx="0.000000" cy="100.000000" r="4"/></svg>`
+	got := svg.Draw(100, 100, geojson2svg.WithPoints(geojson2svg.PointStyle{Radius: 4, Property: "population"}))
+	if got != expected {
+		t.Errorf("expected %s\ngot %s", expected, got)
+	}
+}
+
+func TestSymbolsDeclareXlink(t *testing.T) {
+	symbol := geojson2svg.WithPoints(geojson2svg.PointStyle{Symbol: `<circle r="1"/>`})
+	tcs := []struct {
+		name     string
+		opts     []geojson2svg.Option
+		expected string
+	}{
+		{"without links", nil, `<svg width="100.000000" height="100.000000">`},
+		{"symbol", []geojson2svg.Option{symbol}, `<svg width="100.000000" height="100.000000" xmlns:xlink="http://www.w3.org/1999/xlink">`},
+		{"labels along lines", []geojson2svg.Option{geojson2svg.WithLabels("name", geojson2svg.LabelsAlongLines())}, `<svg width="100.000000" height="100.000000" xmlns:xlink="http://www.w3.org/1999/xlink">`},
+		{"explicit namespace",
+			[]geojson2svg.Option{symbol, geojson2svg.WithAttribute("xmlns:xlink", "http://www.w3.org/1999/xlink")},
+			`<svg width="100.000000" height="100.000000" xmlns:xlink="http://www.w3.org/1999/xlink">`},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			svg := geojson2svg.New()
+			if err := svg.AddGeometry(`{"type": "Point", "coordinates": [0,0]}`); err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			if got := svg.Draw(100, 100, tc.opts...); !strings.HasPrefix(got, tc.expected) {
+				tt.Errorf("expected %s to start with %s", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestLayerSymbolsDeclareXlink(t *testing.T) {
+	svg := geojson2svg.New()
+	svg.Layer("l", geojson2svg.WithPoints(geojson2svg.PointStyle{Symbol: `<circle r="1"/>`}))
+	want := `<svg width="100.000000" height="100.000000" xmlns:xlink="http://www.w3.org/1999/xlink">`
+	if got := svg.Draw(100, 100); !strings.HasPrefix(got, want) {
+		t.Errorf("expected %s to start with %s", got, want)
+	}
+}
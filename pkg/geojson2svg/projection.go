@@ -0,0 +1,128 @@
+package geojson2svg
+
+import "math"
+
+// A Projection transforms geographic coordinates (longitude and latitude in
+// degrees) into planar coordinates. The planar y axis points north, the
+// coordinates are scaled to fit into the svg afterwards.
+type Projection interface {
+	Project(lon, lat float64) (x, y float64)
+}
+
+// The ProjectionFunc type is an adapter to allow the use of ordinary
+// functions as projections.
+type ProjectionFunc func(lon, lat float64) (x, y float64)
+
+// Project calls f(lon, lat).
+func (f ProjectionFunc) Project(lon, lat float64) (x, y float64) {
+	return f(lon, lat)
+}
+
+const (
+	earthRadius     = 6378137.0
+	maxMercatorLat  = 85.0511287798066
+	maxConicLat     = 89.999999
+	degreesToRadian = math.Pi / 180
+)
+
+// WebMercator returns the spherical mercator projection (EPSG:3857) used by
+// most web maps. Latitudes are clamped to ±85.0511°.
+func WebMercator() Projection {
+	return ProjectionFunc(func(lon, lat float64) (float64, float64) {
+		lat = clamp(lat, -maxMercatorLat, maxMercatorLat)
+		x := earthRadius * lon * degreesToRadian
+		y := earthRadius * math.Log(math.Tan(math.Pi/4+lat*degreesToRadian/2))
+		return x, y
+	})
+}
+
+// Equirectangular returns the equirectangular projection with the given
+// standard parallel. A standard parallel of 0 is the plate carrée.
+func Equirectangular(parallel float64) Projection {
+	c := math.Cos(parallel * degreesToRadian)
+	return ProjectionFunc(func(lon, lat float64) (float64, float64) {
+		return lon * degreesToRadian * c, lat * degreesToRadian
+	})
+}
+
+// AlbersEqualArea returns the Albers equal-area conic projection centered on
+// the longitude lon0 and the latitude lat0 with the standard parallels lat1
+// and lat2.
+func AlbersEqualArea(lon0, lat0, lat1, lat2 float64) Projection {
+	phi0, phi1, phi2 := lat0*degreesToRadian, lat1*degreesToRadian, lat2*degreesToRadian
+	n := (math.Sin(phi1) + math.Sin(phi2)) / 2
+
+	if math.Abs(n) < 1e-10 {
+		// the cone degenerates to the cylindrical equal-area projection
+		c := math.Cos(phi1)
+		return ProjectionFunc(func(lon, lat float64) (float64, float64) {
+			return (lon - lon0) * degreesToRadian * c, math.Sin(lat*degreesToRadian) / c
+		})
+	}
+
+	c := math.Cos(phi1)*math.Cos(phi1) + 2*n*math.Sin(phi1)
+	rho0 := math.Sqrt(c-2*n*math.Sin(phi0)) / n
+	return ProjectionFunc(func(lon, lat float64) (float64, float64) {
+		theta := n * (lon - lon0) * degreesToRadian
+		rho := math.Sqrt(c-2*n*math.Sin(lat*degreesToRadian)) / n
+		return rho * math.Sin(theta), rho0 - rho*math.Cos(theta)
+	})
+}
+
+// LambertConformalConic returns the Lambert conformal conic projection
+// centered on the longitude lon0 and the latitude lat0 with the standard
+// parallels lat1 and lat2. The poles are not representable and are clamped.
+func LambertConformalConic(lon0, lat0, lat1, lat2 float64) Projection {
+	phi0, phi1, phi2 := lat0*degreesToRadian, lat1*degreesToRadian, lat2*degreesToRadian
+	t := func(phi float64) float64 { return math.Tan(math.Pi/4 + phi/2) }
+
+	n := math.Sin(phi1)
+	if phi1 != phi2 {
+		n = math.Log(math.Cos(phi1)/math.Cos(phi2)) / math.Log(t(phi2)/t(phi1))
+	}
+
+	if math.Abs(n) < 1e-10 {
+		// the cone degenerates to the mercator projection
+		return ProjectionFunc(func(lon, lat float64) (float64, float64) {
+			lat = clamp(lat, -maxConicLat, maxConicLat)
+			return (lon - lon0) * degreesToRadian, math.Log(t(lat*degreesToRadian)) - math.Log(t(phi0))
+		})
+	}
+
+	f := math.Cos(phi1) * math.Pow(t(phi1), n) / n
+	rho0 := f / math.Pow(t(phi0), n)
+	return ProjectionFunc(func(lon, lat float64) (float64, float64) {
+		lat = clamp(lat, -maxConicLat, maxConicLat)
+		theta := n * (lon - lon0) * degreesToRadian
+		rho := f / math.Pow(t(lat*degreesToRadian), n)
+		return rho * math.Sin(theta), rho0 - rho*math.Cos(theta)
+	})
+}
+
+// WithProjection configures the SVG to project all coordinates with p before
+// they are scaled to fit into the svg. By default the coordinates are used
+// as planar coordinates.
+func WithProjection(p Projection) Option {
+	return func(svg *SVG) {
+		svg.projection = p
+	}
+}
+
+func projectPoints(p Projection, ps [][]float64) [][]float64 {
+	res := make([][]float64, len(ps))
+	for i, pt := range ps {
+		x, y := p.Project(pt[0], pt[1])
+		res[i] = []float64{x, y}
+	}
+	return res
+}
+
+func projectScaleFunc(p Projection, sf scaleFunc) scaleFunc {
+	return func(x, y float64) (float64, float64) {
+		return sf(p.Project(x, y))
+	}
+}
+
+func clamp(v, min, max float64) float64 {
+	return math.Max(min, math.Min(max, v))
+}
@@ -0,0 +1,67 @@
+package geojson2svg_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/fapian/geojson2svg/pkg/geojson2svg"
+)
+
+// The conic test values are the numerical examples of Snyder's
+// "Map Projections: A Working Manual" for the sphere.
+func TestProjections(t *testing.T) {
+	tcs := []struct {
+		name       string
+		projection geojson2svg.Projection
+		lon, lat   float64
+		x, y       float64
+	}{
+		{"web mercator origin", geojson2svg.WebMercator(), 0, 0, 0, 0},
+		{"web mercator antimeridian", geojson2svg.WebMercator(), 180, 0, 20037508.342789, 0},
+		{"web mercator max latitude", geojson2svg.WebMercator(), 0, 85.0511287798066, 0, 20037508.342789},
+		{"web mercator clamps latitude", geojson2svg.WebMercator(), 0, 90, 0, 20037508.342789},
+		{"plate carree", geojson2svg.Equirectangular(0), 90, 45, math.Pi / 2, math.Pi / 4},
+		{"equirectangular", geojson2svg.Equirectangular(60), 90, 45, math.Pi / 4, math.Pi / 4},
+		{"albers origin", geojson2svg.AlbersEqualArea(-96, 23, 29.5, 45.5), -96, 23, 0, 0},
+		{"albers", geojson2svg.AlbersEqualArea(-96, 23, 29.5, 45.5), -75, 35, 0.2952720, 0.2416774},
+		{"albers degenerated", geojson2svg.AlbersEqualArea(0, 0, -30, 30), 90, 30, math.Pi / 2 * math.Cos(math.Pi/6), 0.5 / math.Cos(math.Pi/6)},
+		{"lambert origin", geojson2svg.LambertConformalConic(-96, 23, 33, 45), -96, 23, 0, 0},
+		{"lambert", geojson2svg.LambertConformalConic(-96, 23, 33, 45), -75, 35, 0.2966785, 0.2462112},
+		{"lambert degenerated", geojson2svg.LambertConformalConic(0, 0, 0, 0), 90, 0, math.Pi / 2, 0},
+		{"custom", geojson2svg.ProjectionFunc(func(lon, lat float64) (float64, float64) { return lat, lon }), 1, 2, 2, 1},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			x, y := tc.projection.Project(tc.lon, tc.lat)
+			if math.Abs(x-tc.x) > 1e-6 || math.Abs(y-tc.y) > 1e-6 {
+				tt.Errorf("expected (%f, %f), got (%f, %f)", tc.x, tc.y, x, y)
+			}
+		})
+	}
+}
+
+func TestProjectionOption(t *testing.T) {
+	p := geojson2svg.WebMercator()
+	x1, y1 := p.Project(10, 50)
+	x2, y2 := p.Project(20, 60)
+	x3, y3 := p.Project(15, 70)
+
+	projected := geojson2svg.New()
+	err := projected.AddGeometry(`{"type": "LineString", "coordinates": [[10,50], [20,60], [15,70]]}`)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	planar := geojson2svg.New()
+	err = planar.AddGeometry(fmt.Sprintf(`{"type": "LineString", "coordinates": [[%v,%v], [%v,%v], [%v,%v]]}`, x1, y1, x2, y2, x3, y3))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	want := planar.Draw(400, 400)
+	got := projected.Draw(400, 400, geojson2svg.WithProjection(p))
+	if got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
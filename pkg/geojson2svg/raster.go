@@ -0,0 +1,328 @@
+package geojson2svg
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DrawPNG renders the svg with the given options as PNG image to w.
+// The geometries are scaled, projected, clipped and simplified like Draw
+// does and painted with their fill, stroke, stroke-width, opacity,
+// fill-opacity and stroke-opacity attributes, which are inherited from the
+// layers and the svg root. Stylesheets, labels and legends are not
+// rasterized and points with a symbol are drawn as circles.
+func (svg *SVG) DrawPNG(w io.Writer, width, height float64, opts ...Option) error {
+	img, err := svg.rasterize(width, height, opts...)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
+
+func (svg *SVG) rasterize(width, height float64, opts ...Option) (*image.RGBA, error) {
+	if err := svg.apply(opts); err != nil {
+		return nil, err
+	}
+	r := newRasterRenderer(int(math.Ceil(width)), int(math.Ceil(height)), svg.attributes)
+	if err := svg.render(r, width, height); err != nil {
+		return nil, err
+	}
+	return r.img, nil
+}
+
+// paint are the presentation attributes a raster renderer understands.
+type paint struct {
+	fill, stroke                        string
+	strokeWidth                         float64
+	opacity, fillOpacity, strokeOpacity float64
+}
+
+var defaultPaint = paint{fill: "black", stroke: "none", strokeWidth: 1, opacity: 1, fillOpacity: 1, strokeOpacity: 1}
+
+// with returns the paint p overridden by the attributes, the opacity of
+// groups is approximated by multiplying it into their elements.
+func (p paint) with(attrs map[string]string) paint {
+	if v, ok := attrs["fill"]; ok {
+		p.fill = v
+	}
+	if v, ok := attrs["stroke"]; ok {
+		p.stroke = v
+	}
+	if v, ok := attrs["stroke-width"]; ok {
+		if f, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(v), "px"), 64); err == nil {
+			p.strokeWidth = f
+		}
+	}
+	for _, x := range []struct {
+		name  string
+		value *float64
+	}{{"opacity", &p.opacity}, {"fill-opacity", &p.fillOpacity}, {"stroke-opacity", &p.strokeOpacity}} {
+		if v, ok := attrs[x.name]; ok {
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				f = math.Max(0, math.Min(1, f))
+				if x.name == "opacity" {
+					f *= *x.value
+				}
+				*x.value = f
+			}
+		}
+	}
+	return p
+}
+
+// rasterRenderer renders the elements into an image. Texts are not
+// rendered.
+type rasterRenderer struct {
+	img    *image.RGBA
+	states []rasterState
+}
+
+// rasterState is the state of a group.
+type rasterState struct {
+	paint
+	clip image.Rectangle
+}
+
+func newRasterRenderer(width, height int, attrs map[string]string) *rasterRenderer {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	return &rasterRenderer{
+		img:    img,
+		states: []rasterState{{defaultPaint.with(attrs), img.Bounds()}},
+	}
+}
+
+func (r *rasterRenderer) current() rasterState {
+	return r.states[len(r.states)-1]
+}
+
+func (r *rasterRenderer) group(attrs map[string]string, title, desc string) {
+	s := r.current()
+	r.states = append(r.states, rasterState{s.with(attrs), s.clip})
+}
+
+func (r *rasterRenderer) end() {
+	r.states = r.states[:len(r.states)-1]
+}
+
+func (r *rasterRenderer) clip(x, y, width, height float64) {
+	s := r.current()
+	rect := image.Rect(int(math.Floor(x)), int(math.Floor(y)), int(math.Ceil(x+width)), int(math.Ceil(y+height)))
+	r.states = append(r.states, rasterState{s.paint, s.clip.Intersect(rect)})
+}
+
+func (r *rasterRenderer) text(x, y float64, s string, fontSize float64, anchor string, attrs map[string]string) {
+}
+
+func (r *rasterRenderer) textAlong(line [][]float64, s string, fontSize float64, attrs map[string]string) {
+}
+
+func (r *rasterRenderer) gradient(x, y, width, height float64, colors []string) {
+	drawGradientSteps(r, x, y, width, height, colors)
+}
+
+func (r *rasterRenderer) legend(l Legend, st *styling, width, height float64) {}
+
+func (r *rasterRenderer) defs(css string, ps *pointStyling) {}
+
+func (r *rasterRenderer) circle(x, y, radius float64, attrs map[string]string) {
+	r.path([][][]float64{circleRing(x, y, radius)}, true, attrs)
+}
+
+func (r *rasterRenderer) rect(x, y, width, height float64, attrs map[string]string) {
+	ring := [][]float64{{x, y}, {x + width, y}, {x + width, y + height}, {x, y + height}, {x, y}}
+	r.path([][][]float64{ring}, true, attrs)
+}
+
+func (r *rasterRenderer) path(parts [][][]float64, closed bool, attrs map[string]string) {
+	p := r.current().with(attrs)
+	if c, ok := parseColor(p.fill); ok {
+		// like in SVG open paths are filled as if they were closed
+		r.fill(parts, c, p.opacity*p.fillOpacity)
+	}
+	if c, ok := parseColor(p.stroke); ok && p.strokeWidth > 0 {
+		r.fill(strokeRings(parts, closed, p.strokeWidth), c, p.opacity*p.strokeOpacity)
+	}
+}
+
+func (r *rasterRenderer) symbol(id string, x, y, size float64, attrs map[string]string) {
+	r.circle(x+size/2, y+size/2, size/2, attrs)
+}
+
+func (r *rasterRenderer) err() error {
+	return nil
+}
+
+// edge is an edge of a ring from top to bottom, dir is the direction of
+// the edge in the ring.
+type edge struct {
+	x0, y0, x1, y1 float64
+	dir            int
+}
+
+// crossing is the crossing of a scanline with an edge.
+type crossing struct {
+	x   float64
+	dir int
+}
+
+// subsamples is the number of scanlines per pixel row used for
+// anti-aliasing.
+const subsamples = 4
+
+// fill paints the area of the rings with the color c according to the
+// nonzero fill rule.
+func (r *rasterRenderer) fill(rings [][][]float64, c color.RGBA, alpha float64) {
+	edges := []edge{}
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for _, ring := range rings {
+		for i := range ring {
+			p, q := ring[i], ring[(i+1)%len(ring)]
+			if p[1] == q[1] {
+				continue
+			}
+			e := edge{p[0], p[1], q[0], q[1], 1}
+			if e.y0 > e.y1 {
+				e = edge{q[0], q[1], p[0], p[1], -1}
+			}
+			edges = append(edges, e)
+			minY, maxY = math.Min(minY, e.y0), math.Max(maxY, e.y1)
+		}
+	}
+	if len(edges) == 0 || alpha <= 0 || r.current().clip.Empty() {
+		return
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].y0 < edges[j].y0 })
+
+	b := r.current().clip
+	w := b.Dx()
+	y0 := int(math.Max(float64(b.Min.Y), math.Floor(minY)))
+	y1 := int(math.Min(float64(b.Max.Y), math.Ceil(maxY)))
+	coverage := make([]float64, w)
+	active := []edge{}
+	next := 0
+	xs := []crossing{}
+	for y := y0; y < y1; y++ {
+		for i := range coverage {
+			coverage[i] = 0
+		}
+		for s := 0; s < subsamples; s++ {
+			sy := float64(y) + (float64(s)+0.5)/subsamples
+			for next < len(edges) && edges[next].y0 <= sy {
+				active = append(active, edges[next])
+				next++
+			}
+			n := 0
+			xs = xs[:0]
+			for _, e := range active {
+				if e.y1 <= sy {
+					continue
+				}
+				active[n] = e
+				n++
+				xs = append(xs, crossing{e.x0 + (sy-e.y0)*(e.x1-e.x0)/(e.y1-e.y0), e.dir})
+			}
+			active = active[:n]
+			sort.Slice(xs, func(i, j int) bool { return xs[i].x < xs[j].x })
+			winding := 0
+			for i := 0; i+1 < len(xs); i++ {
+				winding += xs[i].dir
+				if winding != 0 {
+					addSpan(coverage, xs[i].x-float64(b.Min.X), xs[i+1].x-float64(b.Min.X))
+				}
+			}
+		}
+		for x, cv := range coverage {
+			if cv > 0 {
+				r.blend(b.Min.X+x, y, c, math.Min(cv, 1)*alpha)
+			}
+		}
+	}
+}
+
+// addSpan adds the coverage of a subsample scanline from x0 to x1.
+func addSpan(coverage []float64, x0, x1 float64) {
+	x0 = math.Max(x0, 0)
+	x1 = math.Min(x1, float64(len(coverage)))
+	if x1 <= x0 {
+		return
+	}
+	const weight = 1.0 / subsamples
+	i0, i1 := int(x0), int(x1)
+	if i0 == i1 {
+		coverage[i0] += (x1 - x0) * weight
+		return
+	}
+	coverage[i0] += (float64(i0+1) - x0) * weight
+	for i := i0 + 1; i < i1; i++ {
+		coverage[i] += weight
+	}
+	if i1 < len(coverage) {
+		coverage[i1] += (x1 - float64(i1)) * weight
+	}
+}
+
+// blend paints the pixel x, y with the color c and the opacity alpha.
+func (r *rasterRenderer) blend(x, y int, c color.RGBA, alpha float64) {
+	a := alpha * float64(c.A) / 0xff
+	i := r.img.PixOffset(x, y)
+	pix := r.img.Pix[i : i+4]
+	for j, v := range []uint8{c.R, c.G, c.B, 0xff} {
+		pix[j] = uint8(math.Floor(float64(v)*a + float64(pix[j])*(1-a) + 0.5))
+	}
+}
+
+// strokeRings returns the outline of the lines stroked with the given width
+// as rings which all have the same orientation, so they are united by the
+// nonzero fill rule. The lines are joined with round joins.
+func strokeRings(parts [][][]float64, closed bool, width float64) [][][]float64 {
+	hw := width / 2
+	rings := [][][]float64{}
+	for _, part := range parts {
+		n := len(part)
+		if closed && n > 1 && !equalPoints(part[0], part[n-1]) {
+			part = append(append([][]float64{}, part...), part[0])
+			n++
+		}
+		for i := 0; i+1 < n; i++ {
+			p, q := part[i], part[i+1]
+			dx, dy := q[0]-p[0], q[1]-p[1]
+			l := math.Hypot(dx, dy)
+			if l == 0 {
+				continue
+			}
+			nx, ny := -dy/l*hw, dx/l*hw
+			rings = append(rings, orient([][]float64{
+				{p[0] + nx, p[1] + ny}, {q[0] + nx, q[1] + ny}, {q[0] - nx, q[1] - ny}, {p[0] - nx, p[1] - ny},
+			}))
+			if i > 0 || closed {
+				rings = append(rings, orient(circleRing(p[0], p[1], hw)))
+			}
+		}
+	}
+	return rings
+}
+
+// orient returns the ring with a positive signed area.
+func orient(ring [][]float64) [][]float64 {
+	if ringArea(ring) < 0 {
+		return reverse(ring)
+	}
+	return ring
+}
+
+// circleRing returns a polygon approximating the circle.
+func circleRing(x, y, r float64) [][]float64 {
+	n := int(math.Max(8, math.Min(128, math.Ceil(math.Pi*r))))
+	ring := make([][]float64, n)
+	for i := range ring {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		ring[i] = []float64{x + r*math.Cos(a), y + r*math.Sin(a)}
+	}
+	return ring
+}
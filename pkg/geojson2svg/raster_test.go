@@ -0,0 +1,111 @@
+package geojson2svg_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/fapian/geojson2svg/pkg/geojson2svg"
+)
+
+func drawPNG(t *testing.T, svg *geojson2svg.SVG, width, height float64, opts ...geojson2svg.Option) image.Image {
+	buf := &bytes.Buffer{}
+	if err := svg.DrawPNG(buf, width, height, opts...); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	img, err := png.Decode(buf)
+	if err != nil {
+		t.Fatalf("invalid png %v", err)
+	}
+	return img
+}
+
+func rgba(c color.Color) color.RGBA {
+	return color.RGBAModel.Convert(c).(color.RGBA)
+}
+
+func TestDrawPNG(t *testing.T) {
+	tcs := []struct {
+		name    string
+		feature string
+		opts    []geojson2svg.Option
+		pixels  map[image.Point]color.RGBA
+	}{
+		{"filled polygon with hole",
+			`{"type": "Feature", "geometry": {"type": "Polygon", "coordinates": [
+				[[0,0], [20,0], [20,20], [0,20], [0,0]], [[5,5], [5,15], [15,15], [15,5], [5,5]]
+			]}, "properties": {"fill": "red"}}`,
+			[]geojson2svg.Option{geojson2svg.UseProperties([]string{"fill"})},
+			map[image.Point]color.RGBA{
+				{2, 2}:   {0xff, 0, 0, 0xff},
+				{10, 10}: {},
+				{17, 17}: {0xff, 0, 0, 0xff},
+			}},
+		{"stroked line",
+			`{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[0,10], [20,10]]},
+				"properties": {"stroke": "#00ff00", "stroke-width": "4"}}`,
+			[]geojson2svg.Option{geojson2svg.UseProperties([]string{"stroke", "stroke-width"})},
+			map[image.Point]color.RGBA{
+				{10, 10}: {0, 0xff, 0, 0xff},
+				{10, 2}:  {},
+			}},
+		{"opacity",
+			`{"type": "Feature", "geometry": {"type": "Polygon", "coordinates": [[[0,0], [20,0], [20,20], [0,20], [0,0]]]},
+				"properties": {"fill": "blue", "fill-opacity": "0.5"}}`,
+			[]geojson2svg.Option{geojson2svg.UseProperties([]string{"fill", "fill-opacity"})},
+			map[image.Point]color.RGBA{
+				{10, 10}: {0, 0, 0x80, 0x80},
+			}},
+		{"root attributes are inherited",
+			`{"type": "Feature", "geometry": {"type": "Polygon", "coordinates": [[[0,0], [20,0], [20,20], [0,20], [0,0]]]}, "properties": {}}`,
+			[]geojson2svg.Option{geojson2svg.WithAttribute("fill", "#fff")},
+			map[image.Point]color.RGBA{
+				{10, 10}: {0xff, 0xff, 0xff, 0xff},
+			}},
+		{"fill none",
+			`{"type": "Feature", "geometry": {"type": "Polygon", "coordinates": [[[0,0], [20,0], [20,20], [0,20], [0,0]]]}, "properties": {}}`,
+			[]geojson2svg.Option{geojson2svg.WithAttribute("fill", "none")},
+			map[image.Point]color.RGBA{
+				{10, 10}: {},
+			}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			svg := geojson2svg.New()
+			if err := svg.AddFeature(tc.feature); err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			img := drawPNG(tt, svg, 20, 20, append(tc.opts, geojson2svg.WithBounds(0, 0, 20, 20))...)
+			if img.Bounds() != image.Rect(0, 0, 20, 20) {
+				tt.Fatalf("expected a 20x20 image, got %v", img.Bounds())
+			}
+			for p, expected := range tc.pixels {
+				if got := rgba(img.At(p.X, p.Y)); got != expected {
+					tt.Errorf("expected %v at %v, got %v", expected, p, got)
+				}
+			}
+		})
+	}
+}
+
+func TestDrawPNGPoints(t *testing.T) {
+	svg := geojson2svg.New()
+	if err := svg.AddGeometry(`{"type": "MultiPoint", "coordinates": [[0,0], [20,20]]}`); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	img := drawPNG(t, svg, 40, 40,
+		geojson2svg.WithPadding(geojson2svg.Padding{Top: 10, Right: 10, Bottom: 10, Left: 10}),
+		geojson2svg.WithPoints(geojson2svg.PointStyle{Shape: geojson2svg.Square, Radius: 3}))
+	for p, expected := range map[image.Point]color.RGBA{
+		{10, 29}: {0, 0, 0, 0xff},
+		{29, 10}: {0, 0, 0, 0xff},
+		{20, 20}: {},
+	} {
+		if got := rgba(img.At(p.X, p.Y)); got != expected {
+			t.Errorf("expected %v at %v, got %v", expected, p, got)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package geojson2svg
+
+import "fmt"
+
+// renderer emits the geometries of a drawing after they were scaled into
+// the coordinate system of the canvas. The attributes of the elements are
+// SVG presentation attributes, a renderer ignores the ones it can not
+// represent.
+type renderer interface {
+	// group starts a group of elements which share the attributes and the
+	// optional title and description.
+	group(attrs map[string]string, title, desc string)
+	// end ends the current group.
+	end()
+	// defs defines the stylesheet and the point symbol of the current
+	// group.
+	defs(css string, ps *pointStyling)
+	circle(x, y, r float64, attrs map[string]string)
+	rect(x, y, width, height float64, attrs map[string]string)
+	// path draws lines or, if closed, rings.
+	path(parts [][][]float64, closed bool, attrs map[string]string)
+	// symbol draws the point symbol with the given id into the square x, y,
+	// size.
+	symbol(id string, x, y, size float64, attrs map[string]string)
+	// clip starts a group of elements which are clipped to the rectangle.
+	clip(x, y, width, height float64)
+	// text draws the text s vertically centered on y, the anchor x is the
+	// start, middle or end of the text.
+	text(x, y float64, s string, fontSize float64, anchor string, attrs map[string]string)
+	// textAlong draws the text s centered along the line.
+	textAlong(line [][]float64, s string, fontSize float64, attrs map[string]string)
+	// gradient fills the rectangle with the colors as horizontal gradient.
+	gradient(x, y, width, height float64, colors []string)
+	// legend draws the legend of the style.
+	legend(l Legend, st *styling, width, height float64)
+	// err returns the first error of the renderer.
+	err() error
+}
+
+// svgRenderer renders the elements as SVG.
+type svgRenderer struct {
+	w          *errWriter
+	format     formatter
+	attributes *attributeEncoder
+	textPaths  int
+	// prefix is the prefix of the ids of the elements.
+	prefix string
+}
+
+func (r *svgRenderer) group(attrs map[string]string, title, desc string) {
+	fmt.Fprintf(r.w, `<g%s>`, r.attributes.encode(attrs))
+	if title != "" {
+		fmt.Fprintf(r.w, `<title>%s</title>`, escapeText(title))
+	}
+	if desc != "" {
+		fmt.Fprintf(r.w, `<desc>%s</desc>`, escapeText(desc))
+	}
+}
+
+func (r *svgRenderer) end() {
+	fmt.Fprint(r.w, `</g>`)
+}
+
+func (r *svgRenderer) defs(css string, ps *pointStyling) {
+	drawStylesheet(r.w, css)
+	ps.drawSymbol(r.w)
+}
+
+func (r *svgRenderer) circle(x, y, radius float64, attrs map[string]string) {
+	f := r.format
+	fmt.Fprintf(r.w, `<circle cx="%s" cy="%s" r="%s"%s/>`, f.number(x), f.number(y), f.length(radius), r.attributes.encode(attrs))
+}
+
+func (r *svgRenderer) rect(x, y, width, height float64, attrs map[string]string) {
+	f := r.format
+	fmt.Fprintf(r.w, `<rect x="%s" y="%s" width="%s" height="%s"%s/>`,
+		f.number(x), f.number(y), f.length(width), f.length(height), r.attributes.encode(attrs))
+}
+
+func (r *svgRenderer) path(parts [][][]float64, closed bool, attrs map[string]string) {
+	fmt.Fprintf(r.w, `<path d="%s"%s/>`, r.format.path(parts, closed), r.attributes.encode(attrs))
+}
+
+func (r *svgRenderer) symbol(id string, x, y, size float64, attrs map[string]string) {
+	f := r.format
+	fmt.Fprintf(r.w, `<use href="#%[1]s" xlink:href="#%[1]s" x="%s" y="%s" width="%s" height="%s"%s/>`,
+		escapeText(id), f.number(x), f.number(y), f.length(size), f.length(size), r.attributes.encode(attrs))
+}
+
+func (r *svgRenderer) clip(x, y, width, height float64) {
+	f := r.format
+	id := escapeText(r.prefix + "bounds")
+	fmt.Fprintf(r.w, `<defs><clipPath id="%s"><rect x="%s" y="%s" width="%s" height="%s"/></clipPath></defs>`,
+		id, f.number(x), f.number(y), f.number(width), f.number(height))
+	fmt.Fprintf(r.w, `<g clip-path="url(#%s)">`, id)
+}
+
+func (r *svgRenderer) text(x, y float64, s string, fontSize float64, anchor string, attrs map[string]string) {
+	fmt.Fprintf(r.w, `<text x="%s" y="%s" font-size="%g" text-anchor="%s" dominant-baseline="middle"%s>%s</text>`,
+		r.format.number(x), r.format.number(y), fontSize, anchor, r.attributes.encode(attrs), escapeText(s))
+}
+
+func (r *svgRenderer) textAlong(line [][]float64, s string, fontSize float64, attrs map[string]string) {
+	id := escapeText(fmt.Sprintf("%slabel-path-%d", r.prefix, r.textPaths))
+	r.textPaths++
+	fmt.Fprintf(r.w, `<defs><path id="%s" d="%s"/></defs>`, id, r.format.path([][][]float64{line}, false))
+	fmt.Fprintf(r.w, `<text font-size="%g"%s><textPath href="#%[3]s" xlink:href="#%[3]s" startOffset="50%%" text-anchor="middle">%s</textPath></text>`,
+		fontSize, r.attributes.encode(attrs), id, escapeText(s))
+}
+
+func (r *svgRenderer) gradient(x, y, width, height float64, colors []string) {
+	f := r.format
+	id := escapeText(r.prefix + "legend-gradient")
+	fmt.Fprintf(r.w, `<defs><linearGradient id="%s">`, id)
+	for i, c := range colors {
+		offset := 0.0
+		if len(colors) > 1 {
+			offset = float64(i) / float64(len(colors)-1)
+		}
+		fmt.Fprintf(r.w, `<stop offset="%s" stop-color="%s"/>`, f.number(offset), escapeText(c))
+	}
+	fmt.Fprint(r.w, `</linearGradient></defs>`)
+	r.rect(x, y, width, height, map[string]string{"fill": "url(#" + id + ")"})
+}
+
+func (r *svgRenderer) legend(l Legend, st *styling, width, height float64) {
+	drawLegend(r, l, st, width, height)
+}
+
+func (r *svgRenderer) err() error {
+	if r.w.err != nil {
+		return r.w.err
+	}
+	return r.attributes.err
+}
@@ -0,0 +1,212 @@
+package geojson2svg
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The default limits of a Handler.
+const (
+	// DefaultMaxBytes is the default size limit of request bodies.
+	DefaultMaxBytes = 10 << 20
+	// DefaultMaxSize is the default limit of the width and the height of
+	// a drawing in pixels.
+	DefaultMaxSize = 4096
+)
+
+// Handler is a http.Handler which renders the geojson POSTed to it. The
+// body may be a geometry, a feature or a featurecollection. The drawing is
+// configured by the query parameters
+//
+//	width, height  size of the drawing, defaults to 400
+//	padding        padding as 'all', 'vertical,horizontal' or 'top,right,bottom,left'
+//	props          comma separated list of properties copied to the elements
+//	format         svg, png, pdf or html
+//
+// Without format the format is negotiated with the Accept header and
+// defaults to svg. Every request is drawn on a fresh SVG.
+//
+// The Handler has no timeout of its own, wrap it with http.TimeoutHandler
+// and set the timeouts of the http.Server to bound the time of a request.
+type Handler struct {
+	// MaxBytes limits the size of request bodies. Defaults to
+	// DefaultMaxBytes.
+	MaxBytes int64
+	// MaxSize limits the width and the height of the drawings in pixels.
+	// Defaults to DefaultMaxSize.
+	MaxSize float64
+	// Options are applied to every drawing before the options of the
+	// request.
+	Options []Option
+}
+
+// formats maps the formats of a Handler to their content types.
+var formats = []struct {
+	name, contentType string
+}{
+	{"svg", "image/svg+xml"},
+	{"png", "image/png"},
+	{"pdf", "application/pdf"},
+	{"html", "text/html; charset=utf-8"},
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	format := q.Get("format")
+	if format == "" {
+		format = negotiate(r.Header.Get("Accept"))
+		if format == "" {
+			http.Error(w, "not acceptable", http.StatusNotAcceptable)
+			return
+		}
+	}
+	contentType := ""
+	for _, f := range formats {
+		if f.name == format {
+			contentType = f.contentType
+		}
+	}
+	if contentType == "" {
+		http.Error(w, fmt.Sprintf("unknown format: %q", format), http.StatusBadRequest)
+		return
+	}
+
+	maxSize := h.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	width, err := querySize(q.Get("width"), 400, maxSize)
+	if err != nil {
+		http.Error(w, "invalid width: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	height, err := querySize(q.Get("height"), 400, maxSize)
+	if err != nil {
+		http.Error(w, "invalid height: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	opts := append([]Option{}, h.Options...)
+	if v := q.Get("padding"); v != "" {
+		p, err := ParsePadding(v)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if p.Left+p.Right >= width || p.Top+p.Bottom >= height {
+			http.Error(w, "invalid padding: no space left for drawing", http.StatusBadRequest)
+			return
+		}
+		opts = append(opts, WithPadding(p))
+	}
+	if v := q.Get("props"); v != "" {
+		opts = append(opts, UseProperties(ParseProperties(v)))
+	}
+
+	maxBytes := h.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxBytes+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if int64(len(body)) > maxBytes {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	svg := New()
+	if err := svg.AddFromReader(bytes.NewReader(body)); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "svg":
+		err = svg.DrawTo(&buf, width, height, opts...)
+	case "png":
+		err = svg.DrawPNG(&buf, width, height, opts...)
+	case "pdf":
+		err = svg.DrawPDF(&buf, width, height, opts...)
+	case "html":
+		err = svg.DrawHTML(&buf, width, height, HTML{PanZoom: true, Highlight: true}, opts...)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	buf.WriteTo(w)
+}
+
+// negotiate returns the format best matching the Accept header, or "" if
+// no format is acceptable.
+func negotiate(accept string) string {
+	if strings.TrimSpace(accept) == "" {
+		return "svg"
+	}
+	type mediaRange struct {
+		typ string
+		q   float64
+	}
+	rs := []mediaRange{}
+	for _, part := range strings.Split(accept, ",") {
+		typ, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q > 0 {
+			rs = append(rs, mediaRange{typ, q})
+		}
+	}
+	sort.SliceStable(rs, func(i, j int) bool { return rs[i].q > rs[j].q })
+
+	for _, r := range rs {
+		for _, f := range formats {
+			ct := strings.SplitN(f.contentType, ";", 2)[0]
+			if r.typ == ct || r.typ == "*/*" || r.typ == strings.SplitN(ct, "/", 2)[0]+"/*" {
+				return f.name
+			}
+		}
+	}
+	return ""
+}
+
+// querySize parses a width or height, which must be a finite number in
+// (0, max].
+func querySize(s string, def, max float64) (float64, error) {
+	if s == "" {
+		return def, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) || v <= 0 {
+		return 0, fmt.Errorf("not a positive number: %s", s)
+	}
+	if v > max {
+		return 0, fmt.Errorf("larger than %g: %s", max, s)
+	}
+	return v, nil
+}
@@ -0,0 +1,131 @@
+package geojson2svg_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fapian/geojson2svg/pkg/geojson2svg"
+)
+
+func TestHandler(t *testing.T) {
+	feature := `{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[0,0], [2,1]]}, "properties": {"class": "a", "name": "b"}}`
+	tcs := []struct {
+		name        string
+		method      string
+		query       string
+		accept      string
+		body        string
+		status      int
+		contentType string
+		contains    string
+	}{
+		{"svg",
+			"POST", "?width=200&height=100", "", feature,
+			http.StatusOK, "image/svg+xml", `<svg width="200.000000" height="100.000000"><path d="M0.000000 100.000000,200.000000 0.000000" class="a"/></svg>`},
+		{"padding and props",
+			"POST", "?width=200&height=100&padding=10&props=%20name,%20", "", feature,
+			http.StatusOK, "image/svg+xml", `<path d="M10.000000 90.000000,170.000000 10.000000" name="b"/>`},
+		{"format png",
+			"POST", "?format=png", "image/svg+xml", feature,
+			http.StatusOK, "image/png", "\x89PNG"},
+		{"accept pdf",
+			"POST", "", "application/pdf", feature,
+			http.StatusOK, "application/pdf", "%PDF-1.4"},
+		{"accept with quality",
+			"POST", "", "image/svg+xml;q=0.5, text/html", feature,
+			http.StatusOK, "text/html; charset=utf-8", "<!DOCTYPE html>"},
+		{"accept any image",
+			"POST", "", "image/*", feature,
+			http.StatusOK, "image/svg+xml", "<svg"},
+		{"not acceptable",
+			"POST", "", "application/json", feature,
+			http.StatusNotAcceptable, "", ""},
+		{"unknown format",
+			"POST", "?format=gif", "", feature,
+			http.StatusBadRequest, "", ""},
+		{"invalid width",
+			"POST", "?width=a", "", feature,
+			http.StatusBadRequest, "", ""},
+		{"width not a number",
+			"POST", "?width=NaN", "", feature,
+			http.StatusBadRequest, "", ""},
+		{"infinite width",
+			"POST", "?width=Inf&format=png", "", feature,
+			http.StatusBadRequest, "", ""},
+		{"negative height",
+			"POST", "?height=-1", "", feature,
+			http.StatusBadRequest, "", ""},
+		{"too wide",
+			"POST", "?width=40000&height=40000&format=png", "", feature,
+			http.StatusBadRequest, "", ""},
+		{"at the size limit",
+			"POST", "?width=2000&height=10", "", feature,
+			http.StatusOK, "image/svg+xml", `<svg width="2000.000000" height="10.000000">`},
+		{"padding not a number",
+			"POST", "?padding=NaN&format=pdf", "", feature,
+			http.StatusBadRequest, "", ""},
+		{"negative padding",
+			"POST", "?padding=-10", "", feature,
+			http.StatusBadRequest, "", ""},
+		{"padding larger than the drawing",
+			"POST", "?width=400&height=400&padding=300", "", feature,
+			http.StatusBadRequest, "", ""},
+		{"horizontal padding as wide as the drawing",
+			"POST", "?width=400&height=100&padding=0,200", "", feature,
+			http.StatusBadRequest, "", ""},
+		{"invalid padding",
+			"POST", "?padding=1,2,3", "", feature,
+			http.StatusBadRequest, "", ""},
+		{"invalid geojson",
+			"POST", "", "", `{"type": "Foo"}`,
+			http.StatusBadRequest, "", ""},
+		{"short positions",
+			"POST", "", "", `{"type": "LineString", "coordinates": [[1], [2]]}`,
+			http.StatusBadRequest, "", ""},
+		{"empty position",
+			"POST", "", "", `{"type": "Point", "coordinates": []}`,
+			http.StatusBadRequest, "", ""},
+		{"too large",
+			"POST", "", "", strings.Repeat(" ", 1025) + feature,
+			http.StatusRequestEntityTooLarge, "", ""},
+		{"get",
+			"GET", "", "", "",
+			http.StatusMethodNotAllowed, "", ""},
+	}
+
+	h := &geojson2svg.Handler{MaxBytes: 1024, MaxSize: 2000}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			req := httptest.NewRequest(tc.method, "/"+tc.query, strings.NewReader(tc.body))
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tc.status {
+				tt.Fatalf("expected status %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+			if got := rec.Header().Get("Content-Type"); got != tc.contentType {
+				tt.Errorf("expected content type %s, got %s", tc.contentType, got)
+			}
+			if !strings.Contains(rec.Body.String(), tc.contains) {
+				tt.Errorf("expected %q to contain %q", rec.Body.String(), tc.contains)
+			}
+		})
+	}
+}
+
+func TestHandlerOptions(t *testing.T) {
+	h := &geojson2svg.Handler{Options: []geojson2svg.Option{geojson2svg.WithAttribute("class", "map")}}
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"type": "Point", "coordinates": [0,0]}`))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if want := `<svg width="400.000000" height="400.000000" class="map">`; !strings.Contains(rec.Body.String(), want) {
+		t.Errorf("expected %s to contain %s", rec.Body.String(), want)
+	}
+}
@@ -0,0 +1,299 @@
+package geojson2svg
+
+import (
+	"container/heap"
+	"math"
+
+	geojson "github.com/paulmach/go.geojson"
+)
+
+// A Simplifier reduces the number of vertices of a line in svg coordinates.
+// Vertices which deviate less than the tolerance (in pixels) from the
+// simplified line may be removed, the first and the last vertex are kept.
+type Simplifier interface {
+	Simplify(ps [][]float64, tolerance float64) [][]float64
+}
+
+// WithSimplification configures the SVG to simplify all linestrings and
+// polygons after they are scaled into the svg, so the number of vertices
+// depends on the size of the svg rather than on the resolution of the
+// geometries. Lines shared by several geometries, like common borders, are
+// simplified the same way, so no gaps or overlaps appear between them.
+func WithSimplification(s Simplifier, tolerance float64) Option {
+	return func(svg *SVG) {
+		svg.simplifier = s
+		svg.tolerance = tolerance
+	}
+}
+
+// DouglasPeucker returns a simplifier which uses the Ramer–Douglas–Peucker
+// algorithm.
+func DouglasPeucker() Simplifier {
+	return simplifierFunc(douglasPeucker)
+}
+
+// VisvalingamWhyatt returns a simplifier which uses the Visvalingam–Whyatt
+// algorithm. Vertices forming a triangle with their neighbors smaller than
+// the square of the tolerance are removed.
+func VisvalingamWhyatt() Simplifier {
+	return simplifierFunc(visvalingamWhyatt)
+}
+
+type simplifierFunc func(ps [][]float64, tolerance float64) [][]float64
+
+func (f simplifierFunc) Simplify(ps [][]float64, tolerance float64) [][]float64 {
+	if len(ps) < 3 {
+		return ps
+	}
+	return f(ps, tolerance)
+}
+
+func douglasPeucker(ps [][]float64, tolerance float64) [][]float64 {
+	keep := make([]bool, len(ps))
+	keep[0], keep[len(ps)-1] = true, true
+	stack := [][2]int{{0, len(ps) - 1}}
+	for len(stack) > 0 {
+		first, last := stack[len(stack)-1][0], stack[len(stack)-1][1]
+		stack = stack[:len(stack)-1]
+
+		max, index := 0.0, 0
+		for i := first + 1; i < last; i++ {
+			if d := segmentDistance(ps[i][0], ps[i][1], ps[first], ps[last]); d > max {
+				max, index = d, i
+			}
+		}
+		if max > tolerance {
+			keep[index] = true
+			stack = append(stack, [2]int{first, index}, [2]int{index, last})
+		}
+	}
+
+	res := [][]float64{}
+	for i, p := range ps {
+		if keep[i] {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+func visvalingamWhyatt(ps [][]float64, tolerance float64) [][]float64 {
+	vs := make([]*vwVertex, len(ps))
+	for i := range ps {
+		vs[i] = &vwVertex{index: i, prev: i - 1, next: i + 1}
+	}
+	area := func(v *vwVertex) float64 {
+		a, b, c := ps[v.prev], ps[v.index], ps[v.next]
+		return math.Abs((b[0]-a[0])*(c[1]-a[1])-(c[0]-a[0])*(b[1]-a[1])) / 2
+	}
+
+	q := &vwQueue{}
+	for _, v := range vs[1 : len(vs)-1] {
+		v.area = area(v)
+		heap.Push(q, v)
+	}
+
+	removed := make([]bool, len(ps))
+	threshold := tolerance * tolerance
+	for q.Len() > 0 {
+		v := heap.Pop(q).(*vwVertex)
+		if v.area >= threshold {
+			break
+		}
+		removed[v.index] = true
+		prev, next := vs[v.prev], vs[v.next]
+		prev.next, next.prev = v.next, v.prev
+		// the area of a vertex never decreases, so vertices are removed in
+		// the order of their significance
+		for _, n := range []*vwVertex{prev, next} {
+			if n.index == 0 || n.index == len(ps)-1 {
+				continue
+			}
+			n.area = math.Max(area(n), v.area)
+			heap.Fix(q, n.heapIndex)
+		}
+	}
+
+	res := [][]float64{}
+	for i, p := range ps {
+		if !removed[i] {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+type vwVertex struct {
+	index, prev, next int
+	area              float64
+	heapIndex         int
+}
+
+// vwQueue is a min heap of vertices ordered by their area.
+type vwQueue []*vwVertex
+
+func (q vwQueue) Len() int           { return len(q) }
+func (q vwQueue) Less(i, j int) bool { return q[i].area < q[j].area }
+func (q vwQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].heapIndex = i
+	q[j].heapIndex = j
+}
+func (q *vwQueue) Push(x interface{}) {
+	v := x.(*vwVertex)
+	v.heapIndex = len(*q)
+	*q = append(*q, v)
+}
+func (q *vwQueue) Pop() interface{} {
+	old := *q
+	v := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return v
+}
+
+type vertexKey [2]float64
+
+// neighbors are the adjacent vertices of a vertex. A vertex is a node of the
+// topology if it has different neighbors in different lines or if it is
+// the end of a linestring.
+type neighbors struct {
+	a, b vertexKey
+	node bool
+}
+
+// topology records the nodes shared by the lines and rings of the
+// geometries. Between two nodes the lines are simplified independently, so
+// shared lines are simplified the same way.
+type topology map[vertexKey]*neighbors
+
+func newTopology(gs []*geojson.Geometry) topology {
+	t := make(topology)
+	for _, g := range gs {
+		t.add(g)
+	}
+	return t
+}
+
+func (t topology) add(g *geojson.Geometry) {
+	if g == nil {
+		return
+	}
+	switch {
+	case g.IsLineString():
+		t.addLine(g.LineString, false)
+	case g.IsMultiLineString():
+		for _, ps := range g.MultiLineString {
+			t.addLine(ps, false)
+		}
+	case g.IsPolygon():
+		for _, ps := range g.Polygon {
+			t.addLine(ps, true)
+		}
+	case g.IsMultiPolygon():
+		for _, pps := range g.MultiPolygon {
+			for _, ps := range pps {
+				t.addLine(ps, true)
+			}
+		}
+	case g.IsCollection():
+		for _, x := range g.Geometries {
+			t.add(x)
+		}
+	}
+}
+
+func (t topology) addLine(ps [][]float64, closed bool) {
+	if closed && len(ps) > 1 && equalPoints(ps[0], ps[len(ps)-1]) {
+		ps = ps[:len(ps)-1]
+	}
+	for i, p := range ps {
+		k := key(p)
+		prev, next := i-1, i+1
+		if closed {
+			prev, next = (i+len(ps)-1)%len(ps), (i+1)%len(ps)
+		}
+		if prev < 0 || next >= len(ps) {
+			t[k] = &neighbors{node: true}
+			continue
+		}
+		a, b := key(ps[prev]), key(ps[next])
+		n, ok := t[k]
+		switch {
+		case !ok:
+			t[k] = &neighbors{a: a, b: b}
+		case !(n.a == a && n.b == b || n.a == b && n.b == a):
+			n.node = true
+		}
+	}
+}
+
+func (t topology) isNode(p []float64) bool {
+	n, ok := t[key(p)]
+	return ok && n.node
+}
+
+// simplify scales the line into the svg and simplifies the parts of the
+// line between the nodes of the topology.
+func (d *drawer) simplify(ps [][]float64, closed bool) [][]float64 {
+	if closed && len(ps) > 1 && equalPoints(ps[0], ps[len(ps)-1]) {
+		ps = ps[:len(ps)-1]
+		start := -1
+		for i, p := range ps {
+			if d.topology.isNode(p) {
+				start = i
+				break
+			}
+		}
+		if start < 0 {
+			// the ring does not share nodes with other lines
+			ring := append(append([][]float64{}, ps...), ps[0])
+			return d.simplifier.Simplify(scaleLine(d.sf, ring), d.tolerance)
+		}
+		ps = append(append(append([][]float64{}, ps[start:]...), ps[:start]...), ps[start])
+	}
+
+	res := [][]float64{}
+	first := 0
+	for i := 1; i < len(ps); i++ {
+		if i < len(ps)-1 && !d.topology.isNode(ps[i]) {
+			continue
+		}
+		chain := d.simplifyChain(ps[first : i+1])
+		if len(res) > 0 {
+			chain = chain[1:]
+		}
+		res = append(res, chain...)
+		first = i
+	}
+	if len(res) == 0 {
+		return scaleLine(d.sf, ps)
+	}
+	return res
+}
+
+// simplifyChain simplifies the chain always in the same direction, so
+// chains shared by several lines are simplified the same way.
+func (d *drawer) simplifyChain(ps [][]float64) [][]float64 {
+	first, last := key(ps[0]), key(ps[len(ps)-1])
+	reversed := last[0] < first[0] || last[0] == first[0] && last[1] < first[1]
+	if reversed {
+		ps = reverse(ps)
+	}
+	res := d.simplifier.Simplify(scaleLine(d.sf, ps), d.tolerance)
+	if reversed {
+		res = reverse(res)
+	}
+	return res
+}
+
+func key(p []float64) vertexKey {
+	return vertexKey{p[0], p[1]}
+}
+
+func reverse(ps [][]float64) [][]float64 {
+	res := make([][]float64, len(ps))
+	for i, p := range ps {
+		res[len(ps)-1-i] = p
+	}
+	return res
+}
@@ -0,0 +1,90 @@
+package geojson2svg_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/fapian/geojson2svg/pkg/geojson2svg"
+)
+
+func TestSimplification(t *testing.T) {
+	tcs := []struct {
+		name       string
+		simplifier geojson2svg.Simplifier
+		tolerance  float64
+		geometry   string
+		expected   string
+	}{
+		{"douglas peucker",
+			geojson2svg.DouglasPeucker(), 2,
+			`{"type": "LineString", "coordinates": [[0,200], [10,200.2], [20,200], [300,300], [400,200]]}`,
+			`<path d="M0.000000 200.000000,20.000000 200.000000,300.000000 100.000000,400.000000 200.000000"/>`},
+		{"douglas peucker with a high tolerance",
+			geojson2svg.DouglasPeucker(), 200,
+			`{"type": "LineString", "coordinates": [[0,200], [10,200.2], [20,200], [300,300], [400,200]]}`,
+			`<path d="M0.000000 200.000000,400.000000 200.000000"/>`},
+		{"visvalingam whyatt",
+			geojson2svg.VisvalingamWhyatt(), 2,
+			`{"type": "LineString", "coordinates": [[0,200], [10,200.2], [20,200], [300,300], [400,200]]}`,
+			`<path d="M0.000000 200.000000,20.000000 200.000000,300.000000 100.000000,400.000000 200.000000"/>`},
+		{"short linestring",
+			geojson2svg.VisvalingamWhyatt(), 2,
+			`{"type": "LineString", "coordinates": [[0,200], [400,200]]}`,
+			`<path d="M0.000000 200.000000,400.000000 200.000000"/>`},
+		{"polygon",
+			geojson2svg.DouglasPeucker(), 2,
+			`{"type": "Polygon", "coordinates": [[[0,0], [200,1], [400,0], [400,400], [0,400], [0,0]]]}`,
+			`<path d="M0.000000 400.000000,400.000000 400.000000,400.000000 0.000000,0.000000 0.000000,0.000000 400.000000 Z"/>`},
+		{"collapsed polygon",
+			geojson2svg.DouglasPeucker(), 2,
+			`{"type": "Polygon", "coordinates": [[[100,100], [101,100], [101,101], [100,100]]]}`,
+			``},
+		{"collapsed hole",
+			geojson2svg.DouglasPeucker(), 2,
+			`{"type": "Polygon", "coordinates": [[[0,0], [400,0], [400,400], [0,400], [0,0]], [[100,100], [101,100], [101,101], [100,100]]]}`,
+			`<path d="M0.000000 400.000000,400.000000 400.000000,400.000000 0.000000,0.000000 0.000000,0.000000 400.000000 Z"/>`},
+	}
+
+	re := regexp.MustCompile(`<path.*/>`)
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			svg := geojson2svg.New()
+			// fixes the scale to 1:1
+			if err := svg.AddGeometry(`{"type": "MultiPoint", "coordinates": [[0,0], [400,400]]}`); err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			if err := svg.AddGeometry(tc.geometry); err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			got := re.FindString(svg.Draw(400, 400, geojson2svg.WithSimplification(tc.simplifier, tc.tolerance)))
+			if got != tc.expected {
+				tt.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestSimplificationKeepsSharedBorders(t *testing.T) {
+	want := []string{
+		`<path d="M200.000000 400.000000,200.000000 0.000000,0.000000 0.000000,0.000000 400.000000,200.000000 400.000000 Z"/>`,
+		`<path d="M200.000000 400.000000,400.000000 400.000000,400.000000 0.000000,200.000000 0.000000,200.000000 400.000000 Z"/>`,
+	}
+
+	svg := geojson2svg.New()
+	err := svg.AddFeatureCollection(`{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "geometry": {"type": "Polygon", "coordinates": [
+			[[0,0], [200,0], [200,100], [201,200], [200,300], [200,400], [0,400], [0,0]]
+		]}},
+		{"type": "Feature", "geometry": {"type": "Polygon", "coordinates": [
+			[[200,0], [400,0], [400,400], [200,400], [200,300], [201,200], [200,100], [200,0]]
+		]}}
+	]}`)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	got := regexp.MustCompile(`<path[^>]*/>`).FindAllString(svg.Draw(400, 400, geojson2svg.WithSimplification(geojson2svg.DouglasPeucker(), 2)), -1)
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
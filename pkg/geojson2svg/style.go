@@ -0,0 +1,334 @@
+package geojson2svg
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	geojson "github.com/paulmach/go.geojson"
+)
+
+// A Style colors the features according to the value of a feature property.
+// The values are grouped into classes by the classifier and every class is
+// colored with the corresponding color of the ramp.
+type Style struct {
+	// Property is the name of the feature property the colors are based on.
+	Property string
+	// Classifier groups the property values into len(Colors) classes.
+	// If it is nil, the numeric values are mapped continuously onto the
+	// colors.
+	Classifier Classifier
+	// Colors is the color ramp.
+	Colors []string
+	// Attributes are the attributes which are set to the color of a
+	// feature. Defaults to fill.
+	Attributes []string
+}
+
+// A Classifier groups property values into classes.
+type Classifier interface {
+	// Classify returns n classes for the given values. Values which can not
+	// be classified are ignored.
+	Classify(values []interface{}, n int) Classes
+}
+
+// Classes are the result of a classification.
+type Classes interface {
+	// Len returns the number of classes.
+	Len() int
+	// Class returns the index of the class of v or -1 if v does not belong
+	// to a class.
+	Class(v interface{}) int
+	// Label returns a human readable description of the i-th class.
+	Label(i int) string
+}
+
+// WithStyle configures the SVG to color the features according to s.
+// The style attributes take precedence over the attributes copied from the
+// properties.
+func WithStyle(s Style) Option {
+	return func(svg *SVG) {
+		svg.style = &s
+	}
+}
+
+// Quantile returns a classifier which puts the same number of values into
+// every class.
+func Quantile() Classifier {
+	return numericClassifier(func(vs []float64, n int) []float64 {
+		breaks := make([]float64, n+1)
+		breaks[0] = vs[0]
+		for i := 1; i <= n; i++ {
+			breaks[i] = vs[int(math.Ceil(float64(i*len(vs))/float64(n)))-1]
+		}
+		return breaks
+	})
+}
+
+// EqualInterval returns a classifier which divides the range of the values
+// into classes of equal size.
+func EqualInterval() Classifier {
+	return numericClassifier(func(vs []float64, n int) []float64 {
+		min, max := vs[0], vs[len(vs)-1]
+		breaks := make([]float64, n+1)
+		for i := range breaks {
+			breaks[i] = min + float64(i)*(max-min)/float64(n)
+		}
+		breaks[n] = max
+		return breaks
+	})
+}
+
+// NaturalBreaks returns a classifier which uses the Jenks natural breaks
+// optimization to minimize the variance within the classes.
+func NaturalBreaks() Classifier {
+	return numericClassifier(jenksBreaks)
+}
+
+// Categorical returns a classifier which puts every distinct value into its
+// own class. The classes are ordered by their string representation, if
+// there are more classes than colors the colors are repeated.
+func Categorical() Classifier {
+	return categoricalClassifier{}
+}
+
+// numericClassifier computes the class breaks of the sorted values.
+type numericClassifier func(vs []float64, n int) []float64
+
+func (c numericClassifier) Classify(values []interface{}, n int) Classes {
+	vs := []float64{}
+	for _, v := range values {
+		if f, ok := toFloat(v); ok {
+			vs = append(vs, f)
+		}
+	}
+	if len(vs) == 0 || n < 1 {
+		return numericClasses(nil)
+	}
+	sort.Float64s(vs)
+	if n > len(vs) {
+		n = len(vs)
+	}
+	return numericClasses(c(vs, n))
+}
+
+// numericClasses are defined by their breaks, the i-th class contains the
+// values in (breaks[i], breaks[i+1]], the first class contains breaks[0].
+type numericClasses []float64
+
+func (cs numericClasses) Len() int {
+	if len(cs) == 0 {
+		return 0
+	}
+	return len(cs) - 1
+}
+
+func (cs numericClasses) Class(v interface{}) int {
+	f, ok := toFloat(v)
+	if !ok || cs.Len() == 0 || f < cs[0] || f > cs[len(cs)-1] {
+		return -1
+	}
+	return sort.Search(cs.Len()-1, func(i int) bool { return f <= cs[i+1] })
+}
+
+func (cs numericClasses) Label(i int) string {
+	return fmt.Sprintf("%g – %g", cs[i], cs[i+1])
+}
+
+type categoricalClassifier struct{}
+
+func (categoricalClassifier) Classify(values []interface{}, n int) Classes {
+	seen := make(map[string]bool)
+	cs := categoricalClasses{}
+	for _, v := range values {
+		s := fmt.Sprintf("%v", v)
+		if v != nil && !seen[s] {
+			seen[s] = true
+			cs = append(cs, s)
+		}
+	}
+	sort.Strings(cs)
+	return cs
+}
+
+type categoricalClasses []string
+
+func (cs categoricalClasses) Len() int { return len(cs) }
+
+func (cs categoricalClasses) Class(v interface{}) int {
+	if v == nil {
+		return -1
+	}
+	s := fmt.Sprintf("%v", v)
+	i := sort.SearchStrings(cs, s)
+	if i == len(cs) || cs[i] != s {
+		return -1
+	}
+	return i
+}
+
+func (cs categoricalClasses) Label(i int) string { return cs[i] }
+
+// jenksBreaks computes the Jenks natural breaks of the sorted values. There
+// are at most as many classes as distinct values.
+func jenksBreaks(vs []float64, n int) []float64 {
+	distinct := 1
+	for i := 1; i < len(vs); i++ {
+		if vs[i] != vs[i-1] {
+			distinct++
+		}
+	}
+	if n > distinct {
+		n = distinct
+	}
+
+	// lower[l][j] is the index (1 based) of the first value of the last class
+	// of the optimal classification of the first l values into j classes,
+	// variance[l][j] is the variance of that classification.
+	lower := make([][]int, len(vs)+1)
+	variance := make([][]float64, len(vs)+1)
+	for l := range lower {
+		lower[l] = make([]int, n+1)
+		variance[l] = make([]float64, n+1)
+		for j := 1; j <= n; j++ {
+			if l > 1 {
+				variance[l][j] = math.Inf(1)
+			} else {
+				lower[l][j] = 1
+			}
+		}
+	}
+
+	for l := 2; l <= len(vs); l++ {
+		var sum, sumSquares, v float64
+		for m := 1; m <= l; m++ {
+			first := l - m + 1
+			x := vs[first-1]
+			sum += x
+			sumSquares += x * x
+			v = sumSquares - sum*sum/float64(m)
+			if first == 1 {
+				continue
+			}
+			// the first l-m values can not be split into more classes
+			for j := 2; j <= n && j <= first; j++ {
+				if variance[l][j] >= v+variance[first-1][j-1] {
+					lower[l][j] = first
+					variance[l][j] = v + variance[first-1][j-1]
+				}
+			}
+		}
+		lower[l][1] = 1
+		variance[l][1] = v
+	}
+
+	breaks := make([]float64, n+1)
+	breaks[0] = vs[0]
+	breaks[n] = vs[len(vs)-1]
+	l := len(vs)
+	for j := n; j >= 2; j-- {
+		first := lower[l][j]
+		breaks[j-1] = vs[first-2]
+		l = first - 1
+	}
+	return breaks
+}
+
+// styling is a style applied to a set of features.
+type styling struct {
+	Style
+	classes  Classes
+	min, max float64
+}
+
+func newStyling(s Style, fs []*geojson.Feature) *styling {
+	if len(s.Colors) == 0 {
+		return nil
+	}
+	if len(s.Attributes) == 0 {
+		s.Attributes = []string{"fill"}
+	}
+
+	values := make([]interface{}, 0, len(fs))
+	for _, f := range fs {
+		if v, ok := f.Properties[s.Property]; ok {
+			values = append(values, v)
+		}
+	}
+
+	st := &styling{Style: s}
+	if s.Classifier != nil {
+		st.classes = s.Classifier.Classify(values, len(s.Colors))
+		return st
+	}
+
+	st.min, st.max = math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if f, ok := toFloat(v); ok {
+			st.min = math.Min(st.min, f)
+			st.max = math.Max(st.max, f)
+		}
+	}
+	return st
+}
+
+// empty reports whether the style is continuous and none of the features
+// has a numeric value.
+func (st *styling) empty() bool {
+	return st.classes == nil && st.min > st.max
+}
+
+// color returns the color of the property value v.
+func (st *styling) color(v interface{}) (string, bool) {
+	if st.classes != nil {
+		i := st.classes.Class(v)
+		if i < 0 {
+			return "", false
+		}
+		return st.Colors[i%len(st.Colors)], true
+	}
+
+	f, ok := toFloat(v)
+	if !ok {
+		return "", false
+	}
+	t := 0.0
+	if st.max > st.min {
+		t = (f - st.min) / (st.max - st.min)
+	}
+	return interpolateColor(st.Colors, t), true
+}
+
+// apply sets the style attributes of the feature with the given properties.
+func (st *styling) apply(attrs map[string]string, props map[string]interface{}) {
+	if st == nil {
+		return
+	}
+	v, ok := props[st.Property]
+	if !ok {
+		return
+	}
+	c, ok := st.color(v)
+	if !ok {
+		return
+	}
+	for _, a := range st.Attributes {
+		attrs[a] = c
+	}
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case float32:
+		return float64(x), true
+	case int:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	case int32:
+		return float64(x), true
+	}
+	return 0, false
+}
@@ -0,0 +1,154 @@
+package geojson2svg_test
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/fapian/geojson2svg/pkg/geojson2svg"
+	geojson "github.com/paulmach/go.geojson"
+)
+
+func pointsWithProperty(name string, values ...interface{}) *geojson2svg.SVG {
+	svg := geojson2svg.New()
+	for i, v := range values {
+		f := geojson.NewPointFeature([]float64{float64(i), float64(i)})
+		if v != nil {
+			f.SetProperty(name, v)
+		}
+		svg.AddFeatureObject(f)
+	}
+	return svg
+}
+
+func attributeValues(svg, name string) []string {
+	res := []string{}
+	re := regexp.MustCompile(`<circle [^>]*?(?: ` + name + `="([^"]*)")?/>`)
+	for _, m := range re.FindAllStringSubmatch(svg, -1) {
+		res = append(res, m[1])
+	}
+	return res
+}
+
+func TestStyle(t *testing.T) {
+	numbers := []interface{}{1, 2, 3, 10, 11, 12, 50}
+	tcs := []struct {
+		name     string
+		values   []interface{}
+		style    geojson2svg.Style
+		expected []string
+	}{
+		{"equal interval",
+			numbers,
+			geojson2svg.Style{Property: "v", Classifier: geojson2svg.EqualInterval(), Colors: []string{"a", "b", "c"}},
+			[]string{"a", "a", "a", "a", "a", "a", "c"}},
+		{"quantile",
+			numbers,
+			geojson2svg.Style{Property: "v", Classifier: geojson2svg.Quantile(), Colors: []string{"a", "b", "c"}},
+			[]string{"a", "a", "a", "b", "b", "c", "c"}},
+		{"natural breaks",
+			numbers,
+			geojson2svg.Style{Property: "v", Classifier: geojson2svg.NaturalBreaks(), Colors: []string{"a", "b", "c"}},
+			[]string{"a", "a", "a", "b", "b", "b", "c"}},
+		{"more classes than values",
+			[]interface{}{1, 2},
+			geojson2svg.Style{Property: "v", Classifier: geojson2svg.NaturalBreaks(), Colors: []string{"a", "b", "c"}},
+			[]string{"a", "b"}},
+		{"natural breaks with repeated values",
+			[]interface{}{1, 1, 1, 5},
+			geojson2svg.Style{Property: "v", Classifier: geojson2svg.NaturalBreaks(), Colors: []string{"a", "b", "c", "d"}},
+			[]string{"a", "a", "a", "b"}},
+		{"categorical",
+			[]interface{}{"water", "forest", "water", "urban"},
+			geojson2svg.Style{Property: "v", Classifier: geojson2svg.Categorical(), Colors: []string{"a", "b"}},
+			[]string{"a", "a", "a", "b"}},
+		{"continuous",
+			[]interface{}{0, 5, 10},
+			geojson2svg.Style{Property: "v", Colors: []string{"#000", "#fff"}},
+			[]string{"#000000", "#808080", "#ffffff"}},
+		{"continuous with a multi color ramp",
+			[]interface{}{0, 5, 10},
+			geojson2svg.Style{Property: "v", Colors: []string{"red", "lime", "blue"}},
+			[]string{"#ff0000", "#00ff00", "#0000ff"}},
+		{"missing and invalid values",
+			[]interface{}{1, nil, "x", 2},
+			geojson2svg.Style{Property: "v", Classifier: geojson2svg.EqualInterval(), Colors: []string{"a", "b"}},
+			[]string{"a", "", "", "b"}},
+		{"without colors",
+			numbers[:2],
+			geojson2svg.Style{Property: "v", Classifier: geojson2svg.EqualInterval()},
+			[]string{"", ""}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			svg := pointsWithProperty("v", tc.values...)
+			got := attributeValues(svg.Draw(400, 400, geojson2svg.WithStyle(tc.style)), "fill")
+			if !reflect.DeepEqual(got, tc.expected) {
+				tt.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestStyleAttributes(t *testing.T) {
+	want := `<svg width="400.000000" height="400.000000"><path d="M0.000000 291.638796,400.000000 0.000000" class="road" fill="none" stroke="red"/></svg>`
+
+	svg := geojson2svg.New()
+	err := svg.AddFeature(`{"type": "Feature", "properties": {"class": "road", "fill": "none", "stroke": "black", "kind": "highway"},
+		"geometry": {"type": "LineString", "coordinates": [[10.4,20.5], [40.3,42.3]]}}`)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	got := svg.Draw(400, 400,
+		geojson2svg.UseProperties([]string{"class", "fill", "stroke"}),
+		geojson2svg.WithStyle(geojson2svg.Style{
+			Property:   "kind",
+			Classifier: geojson2svg.Categorical(),
+			Colors:     []string{"red"},
+			Attributes: []string{"stroke"},
+		}))
+	if got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestClassesLabels(t *testing.T) {
+	values := []interface{}{1.5, 2.0, 3.0, 10.0, 11.0, 12.0, 50.0}
+	tcs := []struct {
+		name       string
+		classifier geojson2svg.Classifier
+		values     []interface{}
+		expected   []string
+	}{
+		{"numeric", geojson2svg.NaturalBreaks(), values, []string{"1.5 – 3", "3 – 12", "12 – 50"}},
+		{"repeated values", geojson2svg.NaturalBreaks(), []interface{}{1, 1, 1, 5}, []string{"1 – 1", "1 – 5"}},
+		{"equal values", geojson2svg.NaturalBreaks(), []interface{}{2, 2, 2, 2}, []string{"2 – 2"}},
+		{"categorical", geojson2svg.Categorical(), []interface{}{"b", "a", "b", nil}, []string{"a", "b"}},
+		{"no values", geojson2svg.EqualInterval(), []interface{}{"a"}, []string{}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			cs := tc.classifier.Classify(tc.values, 3)
+			got := []string{}
+			for i := 0; i < cs.Len(); i++ {
+				got = append(got, cs.Label(i))
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				tt.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestRamp(t *testing.T) {
+	want := []string{"#000000", "#808080", "#ffffff"}
+	if got := geojson2svg.Ramp("#000", "white", 3); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	want = []string{"#ff0000"}
+	if got := geojson2svg.Ramp("rgb(255, 0, 0)", "blue", 1); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,135 @@
+package geojson2svg
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	geojson "github.com/paulmach/go.geojson"
+)
+
+// A StyleRule is a CSS rule, e.g. the selector ".polygon" with the
+// declarations {"fill": "green"}.
+type StyleRule struct {
+	Selector     string
+	Declarations map[string]string
+}
+
+// WithStylesheet configures the SVG to embed the CSS stylesheet css in a
+// <style> element. It replaces a previously configured stylesheet, the
+// style rules are appended to it.
+func WithStylesheet(css string) Option {
+	return func(svg *SVG) {
+		svg.stylesheet = css
+	}
+}
+
+// WithStyleRules configures the SVG to embed the rules in a <style>
+// element. The declarations of a rule are sorted by their names.
+func WithStyleRules(rules ...StyleRule) Option {
+	return func(svg *SVG) {
+		svg.styleRules = rules
+	}
+}
+
+// WithAutoClasses configures the SVG to assign classes to every feature
+// element based on its geometry type (point, line, polygon or collection)
+// and the values of the given properties, e.g. the property landuse with
+// the value "Forest" results in the class landuse-forest. The classes are
+// prepended to the class copied from the properties.
+func WithAutoClasses(props []string) Option {
+	return func(svg *SVG) {
+		svg.autoClasses = true
+		svg.classProps = props
+	}
+}
+
+// css returns the stylesheet and the style rules as CSS.
+func (svg *SVG) css() string {
+	res := bytes.NewBufferString(svg.stylesheet)
+	for _, r := range svg.styleRules {
+		names := make([]string, 0, len(r.Declarations))
+		for n := range r.Declarations {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(res, "%s{", r.Selector)
+		for _, n := range names {
+			fmt.Fprintf(res, "%s:%s;", n, r.Declarations[n])
+		}
+		res.WriteString("}")
+	}
+	return res.String()
+}
+
+// drawStylesheet draws the <style> element if there is any CSS.
+func drawStylesheet(w io.Writer, css string) {
+	if css == "" {
+		return
+	}
+	// "]]>" would end the CDATA section
+	css = strings.Replace(css, "]]>", "]]]]><![CDATA[>", -1)
+	fmt.Fprintf(w, `<style><![CDATA[%s]]></style>`, css)
+}
+
+// applyClasses prepends the automatic classes of a feature with geometry g
+// and properties props to the class attribute.
+func (svg *SVG) applyClasses(attrs map[string]string, g *geojson.Geometry, props map[string]interface{}) {
+	if !svg.autoClasses {
+		return
+	}
+	classes := []string{}
+	if c := geometryClass(g); c != "" {
+		classes = append(classes, c)
+	}
+	for _, p := range svg.classProps {
+		if v, ok := props[p]; ok && v != nil {
+			classes = append(classes, className(p+"-"+fmt.Sprintf("%v", v)))
+		}
+	}
+	if c := attrs["class"]; c != "" {
+		classes = append(classes, c)
+	}
+	if len(classes) > 0 {
+		attrs["class"] = strings.Join(classes, " ")
+	}
+}
+
+// geometryAttributes returns the attributes of a geometry without feature.
+func (svg *SVG) geometryAttributes(g *geojson.Geometry) map[string]string {
+	attrs := make(map[string]string)
+	svg.applyClasses(attrs, g, nil)
+	return attrs
+}
+
+func geometryClass(g *geojson.Geometry) string {
+	switch {
+	case g == nil:
+		return ""
+	case g.IsPoint(), g.IsMultiPoint():
+		return "point"
+	case g.IsLineString(), g.IsMultiLineString():
+		return "line"
+	case g.IsPolygon(), g.IsMultiPolygon():
+		return "polygon"
+	case g.IsCollection():
+		return "collection"
+	}
+	return ""
+}
+
+// className returns s in lower case with all characters but letters,
+// digits, hyphens and underscores replaced by hyphens.
+func className(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z', '0' <= r && r <= '9', r == '-', r == '_':
+			return r
+		case 'A' <= r && r <= 'Z':
+			return r - 'A' + 'a'
+		}
+		return '-'
+	}, s)
+}
@@ -0,0 +1,64 @@
+package geojson2svg_test
+
+import (
+	"testing"
+
+	"github.com/fapian/geojson2svg/pkg/geojson2svg"
+)
+
+func TestStylesheet(t *testing.T) {
+	tcs := []struct {
+		name     string
+		opts     []geojson2svg.Option
+		expected string
+	}{
+		{"stylesheet",
+			[]geojson2svg.Option{geojson2svg.WithStylesheet(".polygon{fill:green}")},
+			`<svg width="100" height="100"><style><![CDATA[.polygon{fill:green}]]></style>` +
+				`<path d="M0 100,100 100,100 0,0 100 Z" class="c"/></svg>`},
+		{"style rules",
+			[]geojson2svg.Option{
+				geojson2svg.WithStylesheet("path{stroke:none}"),
+				geojson2svg.WithStyleRules(
+					geojson2svg.StyleRule{Selector: ".polygon", Declarations: map[string]string{"fill": "green", "stroke": "black"}},
+					geojson2svg.StyleRule{Selector: ".landuse-forest", Declarations: map[string]string{"fill": "darkgreen"}},
+				),
+			},
+			`<svg width="100" height="100"><style><![CDATA[path{stroke:none}.polygon{fill:green;stroke:black;}.landuse-forest{fill:darkgreen;}]]></style>` +
+				`<path d="M0 100,100 100,100 0,0 100 Z" class="c"/></svg>`},
+		{"cdata end is escaped",
+			[]geojson2svg.Option{geojson2svg.WithStylesheet(`a[title="]]>"]{}`)},
+			`<svg width="100" height="100"><style><![CDATA[a[title="]]]]><![CDATA[>"]{}]]></style>` +
+				`<path d="M0 100,100 100,100 0,0 100 Z" class="c"/></svg>`},
+		{"auto classes",
+			[]geojson2svg.Option{geojson2svg.WithAutoClasses([]string{"landuse", "missing"})},
+			`<svg width="100" height="100"><path d="M0 100,100 100,100 0,0 100 Z" class="polygon landuse-mixed-forest c"/></svg>`},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			svg := geojson2svg.New()
+			err := svg.AddFeature(`{"type": "Feature", "geometry": {"type": "Polygon", "coordinates": [[[0,0], [100,0], [100,100], [0,0]]]},
+				"properties": {"class": "c", "landuse": "Mixed Forest"}}`)
+			if err != nil {
+				tt.Fatalf("unexpected error %v", err)
+			}
+			got := svg.Draw(100, 100, append(tc.opts, geojson2svg.WithPrecision(0))...)
+			if got != tc.expected {
+				tt.Errorf("expected %s\ngot %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestAutoClassesForGeometries(t *testing.T) {
+	svg := geojson2svg.New()
+	if err := svg.AddGeometry(`{"type": "MultiLineString", "coordinates": [[[0,0], [100,100]]]}`); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	expected := `<svg width="100" height="100"><path d="M0 100,100 0" class="line"/></svg>`
+	got := svg.Draw(100, 100, geojson2svg.WithAutoClasses(nil), geojson2svg.WithPrecision(0))
+	if got != expected {
+		t.Errorf("expected %s\ngot %s", expected, got)
+	}
+}
@@ -0,0 +1,174 @@
+package geojson2svg
+
+import (
+	"bytes"
+	"math"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// tile is the tile of the XYZ tile scheme which is drawn.
+type tile struct {
+	z, x, y int
+	buffer  float64
+}
+
+// WithTile configures the SVG to draw the tile z/x/y of the XYZ tile scheme
+// used by most web maps instead of fitting the svg to its geometries. The
+// geometries are projected with WebMercator and clipped buffer pixels
+// outside of the tile, so lines and outlines continue seamlessly into the
+// neighbouring tiles. Only the features intersecting the buffered tile are
+// drawn. The tile should be drawn into a square svg without padding.
+// WithTile replaces the projection, the bounds and the bounds buffer.
+func WithTile(z, x, y int, buffer float64) Option {
+	return func(svg *SVG) {
+		n := math.Exp2(float64(z))
+		svg.projection = WebMercator()
+		svg.bounds = &box{tileLon(float64(x), n), tileLat(float64(y+1), n), tileLon(float64(x+1), n), tileLat(float64(y), n)}
+		svg.center = nil
+		svg.buffer = 0
+		svg.tile = &tile{z: z, x: x, y: y, buffer: buffer}
+	}
+}
+
+// tileLon returns the longitude of the tile column x of n columns.
+func tileLon(x, n float64) float64 {
+	return x/n*360 - 180
+}
+
+// tileLat returns the latitude of the tile row y of n rows.
+func tileLat(y, n float64) float64 {
+	return math.Atan(math.Sinh(math.Pi*(1-2*y/n))) / degreesToRadian
+}
+
+// unprojectMercator returns the longitude and latitude of the WebMercator
+// coordinates x, y.
+func unprojectMercator(x, y float64) (float64, float64) {
+	return x / earthRadius / degreesToRadian, (2*math.Atan(math.Exp(y/earthRadius)) - math.Pi/2) / degreesToRadian
+}
+
+// clipMargin returns the distance in pixels the geometries are clipped
+// outside of the visible extent.
+func (svg *SVG) clipMargin() float64 {
+	if svg.tile != nil {
+		return svg.tile.buffer
+	}
+	return clipMargin
+}
+
+// query returns the box in the coordinates of the geometries which covers
+// the projected extent b grown by m. It returns nil if the box is unknown
+// for the projection.
+func (svg *SVG) query(b box, m float64) *box {
+	q := box{b.minX - m, b.minY - m, b.maxX + m, b.maxY + m}
+	switch {
+	case svg.projection == nil:
+		return &q
+	case svg.tile != nil:
+		q.minX, q.minY = unprojectMercator(q.minX, q.minY)
+		q.maxX, q.maxY = unprojectMercator(q.maxX, q.maxY)
+		return &q
+	}
+	return nil
+}
+
+// TileHandler is a http.Handler which serves the tiles of a SVG under
+// paths ending in /{z}/{x}/{y}.svg or /{z}/{x}/{y}.png. Every tile is
+// drawn on a copy of the SVG, so requests can be served concurrently as
+// long as no data is added to the SVG.
+type TileHandler struct {
+	SVG *SVG
+	// Size is the width and height of the tiles in pixels. Defaults to 256.
+	Size float64
+	// Buffer is the distance in pixels the geometries are clipped outside
+	// of the tiles. Defaults to 8.
+	Buffer float64
+	// Options are applied to every tile.
+	Options []Option
+}
+
+// maxTileZoom is the highest zoom level served by a TileHandler.
+const maxTileZoom = 30
+
+func (h *TileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	z, x, y, format, ok := parseTilePath(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	size := h.Size
+	if size <= 0 {
+		size = 256
+	}
+	buffer := h.Buffer
+	if buffer <= 0 {
+		buffer = clipMargin
+	}
+	opts := append(append([]Option{}, h.Options...), WithTile(z, x, y, buffer))
+
+	svg := h.SVG.clone()
+	var buf bytes.Buffer
+	var err error
+	contentType := "image/svg+xml"
+	if format == "png" {
+		contentType = "image/png"
+		err = svg.DrawPNG(&buf, size, size, opts...)
+	} else {
+		err = svg.DrawTo(&buf, size, size, opts...)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	buf.WriteTo(w)
+}
+
+// parseTilePath returns the tile and the format of a path ending in
+// /{z}/{x}/{y}.svg or /{z}/{x}/{y}.png.
+func parseTilePath(p string) (z, x, y int, format string, ok bool) {
+	parts := strings.Split(p, "/")
+	if len(parts) < 3 {
+		return 0, 0, 0, "", false
+	}
+	parts = parts[len(parts)-3:]
+	format = strings.TrimPrefix(path.Ext(parts[2]), ".")
+	if format != "svg" && format != "png" {
+		return 0, 0, 0, "", false
+	}
+	parts[2] = strings.TrimSuffix(parts[2], "."+format)
+
+	var vs [3]int
+	for i, s := range parts {
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 0 {
+			return 0, 0, 0, "", false
+		}
+		vs[i] = v
+	}
+	z, x, y = vs[0], vs[1], vs[2]
+	if z > maxTileZoom || x >= 1<<uint(z) || y >= 1<<uint(z) {
+		return 0, 0, 0, "", false
+	}
+	return z, x, y, format, true
+}
+
+// clone returns a copy of the svg which can be configured without
+// affecting the svg. The data, the layers and the index are shared.
+func (svg *SVG) clone() *SVG {
+	c := *svg
+	c.attributes = make(map[string]string, len(svg.attributes))
+	for k, v := range svg.attributes {
+		c.attributes[k] = v
+	}
+	return &c
+}
@@ -0,0 +1,154 @@
+package geojson2svg_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fapian/geojson2svg/pkg/geojson2svg"
+)
+
+func tileSVG(t *testing.T) *geojson2svg.SVG {
+	svg := geojson2svg.New()
+	if err := svg.AddGeometry(`{"type": "LineString", "coordinates": [[-180,0], [180,0]]}`); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	err := svg.AddFeatureCollection(`{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "geometry": {"type": "Point", "coordinates": [90,45]}, "properties": {"class": "east"}},
+		{"type": "Feature", "geometry": {"type": "Point", "coordinates": [-90,45]}, "properties": {"class": "west"}},
+		{"type": "Feature", "geometry": {"type": "Polygon", "coordinates": [[[-10,-10],[10,-10],[10,10],[-10,10],[-10,-10]]]}, "properties": {"class": "square"}}
+	]}`)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return svg
+}
+
+func TestWithTile(t *testing.T) {
+	tcs := []struct {
+		name          string
+		z, x, y       int
+		buffer        float64
+		expectedPaths []string
+	}{
+		{"world",
+			0, 0, 0, 8,
+			[]string{
+				`<path d="M0.000000 128.000000,256.000000 128.000000"/>`,
+				`<circle cx="192.000000" cy="92.089609" r="1" class="east"/>`,
+				`<circle cx="64.000000" cy="92.089609" r="1" class="west"/>`,
+				`<path d="M120.888889 135.147491,135.111111 135.147491,135.111111 120.852509,120.888889 120.852509,120.888889 135.147491 Z" class="square"/>`,
+			}},
+		{"north east with buffer",
+			1, 1, 0, 8,
+			[]string{
+				`<path d="M-8.000000 256.000000,256.000000 256.000000"/>`,
+				`<circle cx="128.000000" cy="184.179219" r="1" class="east"/>`,
+				`<path d="M-8.000000 264.000000,14.222222 264.000000,14.222222 241.705017,-8.000000 241.705017,-8.000000 264.000000 Z" class="square"/>`,
+			}},
+		{"south west without buffer",
+			1, 0, 1, 0,
+			[]string{
+				`<path d="M0.000000 0.000000,256.000000 0.000000"/>`,
+				`<path d="M241.777778 0.000000,241.777778 14.294983,256.000000 14.294983,256.000000 0.000000,241.777778 0.000000 Z" class="square"/>`,
+			}},
+	}
+
+	re := regexp.MustCompile(`<(path|circle)[^>]*/>`)
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			got := tileSVG(tt).Draw(256, 256, geojson2svg.WithTile(tc.z, tc.x, tc.y, tc.buffer))
+			if !strings.Contains(got, `<clipPath id="bounds"><rect x="0.000000" y="0.000000" width="256.000000" height="256.000000"/></clipPath>`) {
+				tt.Errorf("expected %s to be clipped to the tile", got)
+			}
+			paths := re.FindAllString(got, -1)
+			if strings.Join(paths, "\n") != strings.Join(tc.expectedPaths, "\n") {
+				tt.Errorf("expected %v, got %v", tc.expectedPaths, paths)
+			}
+		})
+	}
+}
+
+func TestBoundsAfterAddingData(t *testing.T) {
+	svg := geojson2svg.New()
+	if err := svg.AddGeometry(`{"type": "Point", "coordinates": [1,1]}`); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	opt := geojson2svg.WithBounds(0, 0, 10, 10)
+	if got := strings.Count(svg.Draw(100, 100, opt), "<circle"); got != 1 {
+		t.Errorf("expected 1 circle, got %d", got)
+	}
+	if err := svg.AddFeature(`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [2,2]}}`); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got := strings.Count(svg.Draw(100, 100, opt), "<circle"); got != 2 {
+		t.Errorf("expected 2 circles, got %d", got)
+	}
+}
+
+func TestTileHandler(t *testing.T) {
+	tcs := []struct {
+		name        string
+		method      string
+		path        string
+		status      int
+		contentType string
+		contains    string
+	}{
+		{"svg", "GET", "/tiles/1/1/0.svg", http.StatusOK, "image/svg+xml", `<circle cx="128.000000" cy="184.179219" r="1" class="east"/>`},
+		{"png", "GET", "/tiles/0/0/0.png", http.StatusOK, "image/png", "\x89PNG"},
+		{"head", "HEAD", "/0/0/0.svg", http.StatusOK, "image/svg+xml", ""},
+		{"outside of the zoom level", "GET", "/tiles/1/2/0.svg", http.StatusNotFound, "", ""},
+		{"invalid tile", "GET", "/tiles/a/0/0.svg", http.StatusNotFound, "", ""},
+		{"unknown format", "GET", "/tiles/0/0/0.gif", http.StatusNotFound, "", ""},
+		{"too short", "GET", "/0.svg", http.StatusNotFound, "", ""},
+		{"post", "POST", "/tiles/0/0/0.svg", http.StatusMethodNotAllowed, "", ""},
+	}
+
+	h := &geojson2svg.TileHandler{SVG: tileSVG(t)}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(tt *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.path, nil))
+			if rec.Code != tc.status {
+				tt.Fatalf("expected status %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+			if got := rec.Header().Get("Content-Type"); got != tc.contentType {
+				tt.Errorf("expected content type %s, got %s", tc.contentType, got)
+			}
+			if !strings.Contains(rec.Body.String(), tc.contains) {
+				tt.Errorf("expected %q to contain %q", rec.Body.String(), tc.contains)
+			}
+		})
+	}
+}
+
+func TestTileHandlerKeepsSVG(t *testing.T) {
+	svg := tileSVG(t)
+	want := svg.Draw(100, 100)
+
+	h := &geojson2svg.TileHandler{SVG: svg, Size: 512, Options: []geojson2svg.Option{geojson2svg.WithAttribute("class", "tile")}}
+	var wg sync.WaitGroup
+	for _, p := range []string{"/0/0/0.svg", "/1/0/0.svg", "/1/1/0.svg", "/1/0/1.svg", "/1/1/1.png"} {
+		wg.Add(1)
+		go func(p string) {
+			defer wg.Done()
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest("GET", p, nil))
+			if rec.Code != http.StatusOK {
+				t.Errorf("expected status 200 for %s, got %d", p, rec.Code)
+			}
+		}(p)
+	}
+	wg.Wait()
+
+	if got := svg.Draw(100, 100); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}